@@ -2,6 +2,8 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"testing"
 
 	"github.com/robertusnegoro/k8ctl/internal/output"
@@ -30,7 +32,53 @@ func BenchmarkGetPods(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = getPods(client, "default", "", "table", false)
+		_ = getPods(client, "default", "", getOptions{outputFormat: "table"})
+	}
+}
+
+// BenchmarkGetPodsLargeCluster benchmarks listing a large namespace with and without chunking
+func BenchmarkGetPodsLargeCluster(b *testing.B) {
+	client := fake.NewSimpleClientset()
+	for i := 0; i < 10000; i++ {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-pod-%d", i),
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app"}},
+			},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Ready: true, RestartCount: int32(i % 5)}},
+			},
+		}
+		_, _ = client.CoreV1().Pods("default").Create(context.Background(), pod, metav1.CreateOptions{})
+	}
+
+	for _, chunkSize := range []int64{0, DefaultChunkSize} {
+		b.Run(fmt.Sprintf("chunk-size=%d", chunkSize), func(b *testing.B) {
+			opts := getOptions{outputFormat: "table", chunkSize: chunkSize, out: io.Discard}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = getPods(client, "default", "", opts)
+			}
+		})
+	}
+}
+
+// BenchmarkPrintResourcesChunked benchmarks streaming 10k pods served in pages
+func BenchmarkPrintResourcesChunked(b *testing.B) {
+	pods := testPodList(10000)
+	calls := 0
+	lister := pagedPodLister(pods, &calls)
+	opts := getOptions{outputFormat: "table", chunkSize: DefaultChunkSize, out: io.Discard}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = printResources(lister, "", opts)
 	}
 }
 
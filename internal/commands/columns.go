@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// columnOptions holds the flags that add columns to any resource table.
+type columnOptions struct {
+	showKind     bool
+	showLabels   bool
+	labelColumns []string
+}
+
+// addColumnFlags registers --show-kind, --show-labels and -L on cmd.
+func addColumnFlags(cmd *cobra.Command, c *columnOptions) {
+	cmd.Flags().BoolVar(&c.showKind, "show-kind", false, "Prefix names with the resource kind (e.g. pod/web)")
+	cmd.Flags().BoolVar(&c.showLabels, "show-labels", false, "Show all labels as the last column")
+	cmd.Flags().StringSliceVarP(&c.labelColumns, "label-columns", "L", nil, "Show the values of these labels as columns (e.g. -L team,version)")
+}
+
+// headers appends the -L and --show-labels columns to headers.
+func (c columnOptions) headers(headers []string) []string {
+	if len(c.labelColumns) == 0 && !c.showLabels {
+		return headers
+	}
+
+	result := make([]string, 0, len(headers)+len(c.labelColumns)+1)
+	result = append(result, headers...)
+	for _, key := range c.labelColumns {
+		// Like kubectl, prefixed keys are titled by their name only
+		parts := strings.Split(key, "/")
+		result = append(result, strings.ToUpper(parts[len(parts)-1]))
+	}
+	if c.showLabels {
+		result = append(result, "LABELS")
+	}
+	return result
+}
+
+// row prefixes the name in row[0] with kind when --show-kind is set and
+// appends the label values of obj.
+func (c columnOptions) row(kind string, obj metav1.Object, row []string) []string {
+	if c.showKind && kind != "" && len(row) > 0 {
+		row[0] = kind + "/" + row[0]
+	}
+	if len(c.labelColumns) == 0 && !c.showLabels {
+		return row
+	}
+
+	objLabels := map[string]string{}
+	if obj != nil {
+		objLabels = obj.GetLabels()
+	}
+	for _, key := range c.labelColumns {
+		row = append(row, objLabels[key])
+	}
+	if c.showLabels {
+		row = append(row, labels.FormatLabels(objLabels))
+	}
+	return row
+}
+
+// displayKind is the kind prefix kubectl uses for a resource, e.g.
+// "pod" or "deployment.apps".
+func displayKind(kind, group string) string {
+	kind = strings.ToLower(kind)
+	if group == "" {
+		return kind
+	}
+	return kind + "." + group
+}
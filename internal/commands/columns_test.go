@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestColumnOptionsHeaders(t *testing.T) {
+	headers := []string{"NAME", "AGE"}
+
+	if got := (columnOptions{}).headers(headers); !reflect.DeepEqual(got, headers) {
+		t.Errorf("Expected headers unchanged, got %v", got)
+	}
+
+	c := columnOptions{showLabels: true, labelColumns: []string{"team", "app.kubernetes.io/version"}}
+	expected := []string{"NAME", "AGE", "TEAM", "VERSION", "LABELS"}
+	if got := c.headers(headers); !reflect.DeepEqual(got, expected) {
+		t.Errorf("headers() = %v, expected %v", got, expected)
+	}
+	if len(headers) != 2 {
+		t.Errorf("headers() should not modify its input, got %v", headers)
+	}
+}
+
+func TestColumnOptionsRow(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:   "web",
+		Labels: map[string]string{"app": "web", "team": "payments"},
+	}}
+
+	c := columnOptions{showKind: true, showLabels: true, labelColumns: []string{"team", "missing"}}
+	expected := []string{"pod/web", "1m", "payments", "", "app=web,team=payments"}
+	if got := c.row("pod", pod, []string{"web", "1m"}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("row() = %v, expected %v", got, expected)
+	}
+
+	unlabelled := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db"}}
+	c = columnOptions{showLabels: true}
+	expected = []string{"db", "<none>"}
+	if got := c.row("pod", unlabelled, []string{"db"}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("row() = %v, expected %v", got, expected)
+	}
+}
+
+func TestDisplayKind(t *testing.T) {
+	if got := displayKind("Pod", ""); got != "pod" {
+		t.Errorf("displayKind(Pod) = %q, expected pod", got)
+	}
+	if got := displayKind("Deployment", "apps"); got != "deployment.apps" {
+		t.Errorf("displayKind(Deployment, apps) = %q, expected deployment.apps", got)
+	}
+}
+
+func TestGetPodsWithLabelColumns(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "prod", Labels: map[string]string{"team": "payments"}}},
+	)
+
+	var buf bytes.Buffer
+	opts := getOptions{
+		outputFormat:  "table",
+		showNamespace: true,
+		columns:       columnOptions{showKind: true, showLabels: true, labelColumns: []string{"team"}},
+		out:           &buf,
+	}
+	if err := getPods(clientset, metav1.NamespaceAll, "", opts); err != nil {
+		t.Fatalf("getPods failed: %v", err)
+	}
+
+	result := buf.String()
+	for _, expected := range []string{"TEAM", "LABELS", "pod/api-0", "payments", "team=payments"} {
+		if !strings.Contains(strings.ToUpper(result), strings.ToUpper(expected)) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, result)
+		}
+	}
+	// The namespace column stays first and is not prefixed with the kind
+	if !strings.Contains(result, "prod") || strings.Contains(result, "pod/prod") {
+		t.Errorf("Expected an unprefixed namespace column, got:\n%s", result)
+	}
+}
@@ -5,6 +5,10 @@ const (
 	OutputFormatJSON  = "json"
 	OutputFormatYAML  = "yaml"
 	OutputFormatTable = "table"
+	OutputFormatWide  = "wide"
+	// OutputFormatEvents and OutputFormatJSONL print watch events one per line
+	OutputFormatEvents = "events"
+	OutputFormatJSONL  = "jsonl"
 )
 
 // Status constants
@@ -43,4 +47,32 @@ const (
 	ResourceSecret          = "secret"
 	ResourceSecrets         = "secrets"
 	ResourceSec             = "sec"
+	ResourceNode            = "node"
+	ResourceNodes           = "nodes"
+	ResourceNo              = "no"
+	ResourceNamespace       = "namespace"
+	ResourceNamespaces      = "namespaces"
+	ResourceNs              = "ns"
+	ResourceStatefulSet     = "statefulset"
+	ResourceStatefulSets    = "statefulsets"
+	ResourceSts             = "sts"
+	ResourceDaemonSet       = "daemonset"
+	ResourceDaemonSets      = "daemonsets"
+	ResourceDs              = "ds"
+	ResourceReplicaSet      = "replicaset"
+	ResourceReplicaSets     = "replicasets"
+	ResourceRs              = "rs"
+	ResourceJob             = "job"
+	ResourceJobs            = "jobs"
+	ResourceCronJob         = "cronjob"
+	ResourceCronJobs        = "cronjobs"
+	ResourceCj              = "cj"
+
+	ResourcePersistentVolumeClaim    = "persistentvolumeclaim"
+	ResourcePersistentVolumeClaims   = "persistentvolumeclaims"
+	ResourcePvc                      = "pvc"
+	ResourcePersistentVolumes        = "persistentvolumes"
+	ResourceHorizontalPodAutoscaler  = "horizontalpodautoscaler"
+	ResourceHorizontalPodAutoscalers = "horizontalpodautoscalers"
+	ResourceHpa                      = "hpa"
 )
@@ -3,41 +3,82 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/robertusnegoro/k8ctl/internal/config"
 	"github.com/robertusnegoro/k8ctl/internal/errors"
 	"github.com/robertusnegoro/k8ctl/internal/k8s"
 	"github.com/robertusnegoro/k8ctl/internal/output"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/kubernetes"
 )
 
+// describeOptions holds the describe flags shared by every describer.
+type describeOptions struct {
+	outputFormat string
+	// decode shows secret values instead of redacting them
+	decode bool
+	// out is where the description is written; nil means stdout
+	out io.Writer
+}
+
+func (o describeOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+// print writes obj in the requested structured format, or as a description
+// built by describe when no format was given.
+func (o describeOptions) print(obj interface{}, describe func(d *output.DescribeWriter)) error {
+	if o.outputFormat != "" {
+		return output.WriteObjects(o.writer(), obj, o.outputFormat)
+	}
+	d := output.NewDescribeWriter(o.writer())
+	describe(d)
+	return d.Flush()
+}
+
 // NewDescribeCommand creates a new describe command for showing resource details.
 func NewDescribeCommand() *cobra.Command {
 	var namespace string
+	var opts describeOptions
 
 	cmd := &cobra.Command{
 		Use:   "describe [resource-type] [resource-name]",
 		Short: "Show details of a specific resource",
-		Long: `Show detailed information about a Kubernetes resource with
-formatted YAML/JSON output and syntax highlighting.`,
+		Long: `Show detailed information about a Kubernetes resource: its metadata,
+status, conditions, related objects and recent events. Use -o to print the
+object as YAML, JSON or a template instead.`,
 		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDescribe(cmd, args, namespace)
+			return runDescribe(cmd, args, namespace, opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (overrides config)")
+	cmd.Flags().StringVarP(&opts.outputFormat, "output", "o", "", "Output format: yaml, json, jsonpath=..., go-template=..., custom-columns=... (or the -file variants); defaults to a description")
+	cmd.Flags().BoolVar(&opts.decode, "decode", false, "Show decoded secret values instead of redacting them; -o output then lists them under stringData")
 
 	return cmd
 }
 
-func runDescribe(_ *cobra.Command, args []string, namespace string) error {
+func runDescribe(_ *cobra.Command, args []string, namespace string, opts describeOptions) error {
 	resourceType := args[0]
 	resourceName := args[1]
 
+	if opts.outputFormat != "" && !output.IsStructuredFormat(opts.outputFormat) {
+		return fmt.Errorf("unsupported output format for describe: %s", opts.outputFormat)
+	}
+
 	client, err := k8s.GetClient()
 	if err != nil {
 		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
@@ -52,36 +93,41 @@ func runDescribe(_ *cobra.Command, args []string, namespace string) error {
 	}
 
 	// Expand shortcuts (same as in get command)
-	resourceShortcuts := map[string]string{
-		"po":     "pods",
-		"deploy": "deployments",
-		"svc":    "services",
-		"ing":    "ingresses",
-		"cm":     "configmaps",
-		"sec":    "secrets",
-		"ns":     "namespaces",
-		"no":     "nodes",
-		"sa":     "serviceaccounts",
-	}
 	if expanded, ok := resourceShortcuts[strings.ToLower(resourceType)]; ok {
 		resourceType = expanded
 	}
 
 	switch strings.ToLower(resourceType) {
 	case ResourcePod, ResourcePods, ResourcePo:
-		return describePod(client, namespace, resourceName)
+		return describePod(client, namespace, resourceName, opts)
 	case ResourceDeployment, ResourceDeployments, ResourceDeploy:
-		return describeDeployment(client, namespace, resourceName)
+		return describeDeployment(client, namespace, resourceName, opts)
 	case ResourceService, ResourceServices, ResourceSvc:
-		return describeService(client, namespace, resourceName)
+		return describeService(client, namespace, resourceName, opts)
 	case ResourceConfigMap, ResourceConfigMaps, ResourceCm:
-		return describeConfigMap(client, namespace, resourceName)
+		return describeConfigMap(client, namespace, resourceName, opts)
 	case ResourceSecret, ResourceSecrets, ResourceSec:
-		return describeSecret(client, namespace, resourceName)
+		return describeSecret(client, namespace, resourceName, opts)
 	case ResourceIngress, ResourceIngresses, ResourceIng:
-		return describeIngress(client, namespace, resourceName)
+		return describeIngress(client, namespace, resourceName, opts)
 	case ResourceServiceAccount, ResourceServiceAccounts, ResourceSa:
-		return describeServiceAccount(client, namespace, resourceName)
+		return describeServiceAccount(client, namespace, resourceName, opts)
+	case ResourceNode, ResourceNodes, ResourceNo:
+		return describeNode(client, resourceName, opts)
+	case ResourceNamespace, ResourceNamespaces, ResourceNs:
+		return describeNamespace(client, resourceName, opts)
+	case ResourceStatefulSet, ResourceStatefulSets, ResourceSts:
+		return describeStatefulSet(client, namespace, resourceName, opts)
+	case ResourceDaemonSet, ResourceDaemonSets, ResourceDs:
+		return describeDaemonSet(client, namespace, resourceName, opts)
+	case ResourceJob, ResourceJobs:
+		return describeJob(client, namespace, resourceName, opts)
+	case ResourceCronJob, ResourceCronJobs, ResourceCj:
+		return describeCronJob(client, namespace, resourceName, opts)
+	case ResourcePersistentVolumeClaim, ResourcePersistentVolumeClaims, ResourcePvc:
+		return describePersistentVolumeClaim(client, namespace, resourceName, opts)
+	case ResourceHorizontalPodAutoscaler, ResourceHorizontalPodAutoscalers, ResourceHpa:
+		return describeHorizontalPodAutoscaler(client, namespace, resourceName, opts)
 	default:
 		return errors.WrapError(
 			fmt.Errorf("resource type %s not yet implemented", resourceType),
@@ -90,66 +136,134 @@ func runDescribe(_ *cobra.Command, args []string, namespace string) error {
 	}
 }
 
-func describePod(client kubernetes.Interface, namespace, name string) error {
-	pod, err := client.CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
-	if err != nil {
-		return errors.HandleKubernetesError(err, "pod", name, namespace)
+// describeMetadata writes the fields every object shares. Like kubectl, it
+// leaves out the last-applied annotation, which repeats the whole object and,
+// for secrets, their values.
+func describeMetadata(d *output.DescribeWriter, obj metav1.Object) {
+	d.Field(0, "Name", obj.GetName())
+	if obj.GetNamespace() != "" {
+		d.Field(0, "Namespace", obj.GetNamespace())
+	}
+	describeMap(d, 0, "Labels", obj.GetLabels())
+	annotations := make(map[string]string, len(obj.GetAnnotations()))
+	for key, value := range obj.GetAnnotations() {
+		if key != corev1.LastAppliedConfigAnnotation {
+			annotations[key] = value
+		}
+	}
+	describeMap(d, 0, "Annotations", annotations)
+	d.Field(0, "CreationTimestamp", formatTimestamp(obj.GetCreationTimestamp()))
+	if refs := obj.GetOwnerReferences(); len(refs) > 0 {
+		owners := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			owners = append(owners, ref.Kind+"/"+ref.Name)
+		}
+		d.Field(0, "Controlled By", strings.Join(owners, ", "))
 	}
-
-	// Format as YAML with sections
-	return output.FormatYAML(pod)
 }
 
-func describeDeployment(client kubernetes.Interface, namespace, name string) error {
-	deployment, err := client.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
-	if err != nil {
-		return errors.HandleKubernetesError(err, "deployment", name, namespace)
+// describeMap writes one key=value pair per line, sorted by key.
+func describeMap(d *output.DescribeWriter, indent int, label string, values map[string]string) {
+	if len(values) == 0 {
+		d.Field(indent, label, "")
+		return
 	}
 
-	return output.FormatYAML(deployment)
+	keys := sortedKeys(values)
+	d.Field(indent, label, keys[0]+"="+values[keys[0]])
+	for _, key := range keys[1:] {
+		d.Row(indent, "", key+"="+values[key])
+	}
 }
 
-func describeService(client kubernetes.Interface, namespace, name string) error {
-	service, err := client.CoreV1().Services(namespace).Get(context.Background(), name, metav1.GetOptions{})
-	if err != nil {
-		return errors.HandleKubernetesError(err, "service", name, namespace)
+// describeList writes one value per line under label.
+func describeList(d *output.DescribeWriter, indent int, label string, values []string) {
+	if len(values) == 0 {
+		d.Field(indent, label, "")
+		return
+	}
+	d.Field(indent, label, values[0])
+	for _, value := range values[1:] {
+		d.Row(indent, "", value)
 	}
-
-	return output.FormatYAML(service)
 }
 
-func describeConfigMap(client kubernetes.Interface, namespace, name string) error {
-	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(context.Background(), name, metav1.GetOptions{})
-	if err != nil {
-		return errors.HandleKubernetesError(err, "configmap", name, namespace)
+// describeEvents lists the events recorded for an object, oldest first.
+func describeEvents(d *output.DescribeWriter, client kubernetes.Interface, kind string, obj metav1.Object) {
+	uid := string(obj.GetUID())
+	if kind == "Node" {
+		// The kubelet records node events with the node name as the UID
+		uid = obj.GetName()
 	}
+	selector := fields.Set{
+		"involvedObject.kind":      kind,
+		"involvedObject.name":      obj.GetName(),
+		"involvedObject.namespace": obj.GetNamespace(),
+		"involvedObject.uid":       uid,
+	}.AsSelector().String()
 
-	return output.FormatYAML(configMap)
-}
-
-func describeSecret(client kubernetes.Interface, namespace, name string) error {
-	secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	events, err := client.CoreV1().Events(obj.GetNamespace()).List(context.Background(), metav1.ListOptions{FieldSelector: selector})
 	if err != nil {
-		return errors.HandleKubernetesError(err, "secret", name, namespace)
+		d.Field(0, "Events", fmt.Sprintf("<unable to list events: %v>", err))
+		return
+	}
+	if len(events.Items) == 0 {
+		d.Field(0, "Events", "")
+		return
 	}
 
-	return output.FormatYAML(secret)
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return eventTime(&events.Items[i]).Before(eventTime(&events.Items[j]))
+	})
+
+	d.Section(0, "Events")
+	d.Row(1, "Type", "Reason", "Age", "From", "Message")
+	d.Row(1, "----", "------", "----", "----", "-------")
+	for i := range events.Items {
+		event := &events.Items[i]
+		eventType := event.Type
+		if eventType == corev1.EventTypeWarning {
+			eventType = output.StatusWarning.Sprint(eventType)
+		}
+		d.Row(1, eventType, event.Reason, eventAge(event), eventSource(event), strings.TrimSpace(event.Message))
+	}
 }
 
-func describeIngress(client kubernetes.Interface, namespace, name string) error {
-	ingress, err := client.NetworkingV1().Ingresses(namespace).Get(context.Background(), name, metav1.GetOptions{})
-	if err != nil {
-		return errors.HandleKubernetesError(err, "ingress", name, namespace)
+// eventTime is when an event was last seen, for both the old and new event APIs.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
 	}
+}
 
-	return output.FormatYAML(ingress)
+// eventAge formats like kubectl, e.g. "2m (x5 over 10m)".
+func eventAge(event *corev1.Event) string {
+	age := getAge(metav1.NewTime(eventTime(event)))
+	if event.Count > 1 && !event.FirstTimestamp.IsZero() {
+		return fmt.Sprintf("%s (x%d over %s)", age, event.Count, getAge(event.FirstTimestamp))
+	}
+	return age
 }
 
-func describeServiceAccount(client kubernetes.Interface, namespace, name string) error {
-	serviceAccount, err := client.CoreV1().ServiceAccounts(namespace).Get(context.Background(), name, metav1.GetOptions{})
-	if err != nil {
-		return errors.HandleKubernetesError(err, "serviceaccount", name, namespace)
+func eventSource(event *corev1.Event) string {
+	source := event.Source.Component
+	if source == "" {
+		source = event.ReportingController
+	}
+	if event.Source.Host != "" {
+		source += ", " + event.Source.Host
 	}
+	return source
+}
 
-	return output.FormatYAML(serviceAccount)
+func formatTimestamp(t metav1.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC1123Z)
 }
@@ -0,0 +1,287 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+func describeNode(client kubernetes.Interface, name string, opts describeOptions) error {
+	node, err := client.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "node", name, "")
+	}
+
+	return opts.print(node, func(d *output.DescribeWriter) {
+		describeNodeText(d, client, node)
+	})
+}
+
+func describeNodeText(d *output.DescribeWriter, client kubernetes.Interface, node *corev1.Node) {
+	d.Field(0, "Name", node.Name)
+	d.Field(0, "Roles", strings.Join(getNodeRoles(node), ","))
+	describeMap(d, 0, "Labels", node.Labels)
+	describeMap(d, 0, "Annotations", node.Annotations)
+	d.Field(0, "CreationTimestamp", formatTimestamp(node.CreationTimestamp))
+
+	taints := make([]string, 0, len(node.Spec.Taints))
+	for _, taint := range node.Spec.Taints {
+		taints = append(taints, taint.ToString())
+	}
+	describeList(d, 0, "Taints", taints)
+	d.Field(0, "Unschedulable", fmt.Sprintf("%t", node.Spec.Unschedulable))
+
+	if len(node.Status.Conditions) > 0 {
+		d.Section(0, "Conditions")
+		d.Row(1, "Type", "Status", "LastHeartbeatTime", "Reason", "Message")
+		d.Row(1, "----", "------", "-----------------", "------", "-------")
+		for _, condition := range node.Status.Conditions {
+			d.Row(1, string(condition.Type), colorizeNodeCondition(condition),
+				formatTimestamp(condition.LastHeartbeatTime), condition.Reason, condition.Message)
+		}
+	}
+
+	if len(node.Status.Addresses) > 0 {
+		d.Section(0, "Addresses")
+		for _, address := range node.Status.Addresses {
+			d.Field(1, string(address.Type), address.Address)
+		}
+	}
+	describeResources(d, 0, "Capacity", node.Status.Capacity)
+	describeResources(d, 0, "Allocatable", node.Status.Allocatable)
+
+	info := node.Status.NodeInfo
+	d.Section(0, "System Info")
+	d.Field(1, "Kernel Version", info.KernelVersion)
+	d.Field(1, "OS Image", info.OSImage)
+	d.Field(1, "Architecture", info.Architecture)
+	d.Field(1, "Container Runtime Version", info.ContainerRuntimeVersion)
+	d.Field(1, "Kubelet Version", info.KubeletVersion)
+	d.Field(0, "PodCIDR", node.Spec.PodCIDR)
+
+	describeNodePods(d, client, node)
+	describeEvents(d, client, "Node", node)
+}
+
+// colorizeNodeCondition colors a node condition by health: Ready should be
+// True, while pressure conditions should be False.
+func colorizeNodeCondition(condition corev1.NodeCondition) string {
+	healthy := condition.Status == corev1.ConditionFalse
+	if condition.Type == corev1.NodeReady {
+		healthy = condition.Status == corev1.ConditionTrue
+	}
+	switch {
+	case condition.Status == corev1.ConditionUnknown:
+		return output.StatusWarning.Sprint(condition.Status)
+	case healthy:
+		return output.StatusRunning.Sprint(condition.Status)
+	default:
+		return output.StatusError.Sprint(condition.Status)
+	}
+}
+
+// describeNodePods lists the non-terminated pods on a node and sums their
+// requests and limits against the node's allocatable resources.
+func describeNodePods(d *output.DescribeWriter, client kubernetes.Interface, node *corev1.Node) {
+	selector := fields.AndSelectors(
+		fields.OneTermEqualSelector("spec.nodeName", node.Name),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded)),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodFailed)),
+	).String()
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		d.Field(0, "Non-terminated Pods", fmt.Sprintf("<unable to list pods: %v>", err))
+		return
+	}
+
+	allocatable := node.Status.Allocatable
+	totalRequests, totalLimits := corev1.ResourceList{}, corev1.ResourceList{}
+
+	d.Section(0, fmt.Sprintf("Non-terminated Pods (%d in total)", len(pods.Items)))
+	if len(pods.Items) > 0 {
+		d.Row(1, "Namespace", "Name", "CPU Requests", "CPU Limits", "Memory Requests", "Memory Limits", "Age")
+		d.Row(1, "---------", "----", "------------", "----------", "---------------", "-------------", "---")
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		requests, limits := podRequestsAndLimits(pod)
+		addResources(totalRequests, requests)
+		addResources(totalLimits, limits)
+		d.Row(1, pod.Namespace, pod.Name,
+			formatAllocation(requests, allocatable, corev1.ResourceCPU),
+			formatAllocation(limits, allocatable, corev1.ResourceCPU),
+			formatAllocation(requests, allocatable, corev1.ResourceMemory),
+			formatAllocation(limits, allocatable, corev1.ResourceMemory),
+			getAge(pod.CreationTimestamp))
+	}
+
+	d.Section(0, "Allocated resources")
+	d.Row(1, "(Total limits may be over 100 percent, i.e., overcommitted.)")
+	d.Row(1, "Resource", "Requests", "Limits")
+	d.Row(1, "--------", "--------", "------")
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage} {
+		d.Row(1, string(name), formatAllocation(totalRequests, allocatable, name), formatAllocation(totalLimits, allocatable, name))
+	}
+}
+
+// podRequestsAndLimits computes what a pod reserves on its node: the sum of its
+// containers, or the largest init container if that is bigger, plus overhead.
+func podRequestsAndLimits(pod *corev1.Pod) (requests, limits corev1.ResourceList) {
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	for i := range pod.Spec.Containers {
+		addResources(requests, pod.Spec.Containers[i].Resources.Requests)
+		addResources(limits, pod.Spec.Containers[i].Resources.Limits)
+	}
+	for i := range pod.Spec.InitContainers {
+		maxResources(requests, pod.Spec.InitContainers[i].Resources.Requests)
+		maxResources(limits, pod.Spec.InitContainers[i].Resources.Limits)
+	}
+	if pod.Spec.Overhead != nil {
+		addResources(requests, pod.Spec.Overhead)
+		for name, quantity := range pod.Spec.Overhead {
+			if _, ok := limits[name]; ok {
+				addResources(limits, corev1.ResourceList{name: quantity})
+			}
+		}
+	}
+	return requests, limits
+}
+
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		if value, ok := total[name]; ok {
+			value.Add(quantity)
+			total[name] = value
+		} else {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func maxResources(total, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if value, ok := total[name]; !ok || quantity.Cmp(value) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// formatAllocation formats a quantity with its share of the allocatable
+// amount, e.g. "250m (12%)".
+func formatAllocation(resources, allocatable corev1.ResourceList, name corev1.ResourceName) string {
+	quantity, ok := resources[name]
+	if !ok {
+		quantity = *resource.NewQuantity(0, resource.DecimalSI)
+	}
+	percent := int64(0)
+	if total, ok := allocatable[name]; ok && total.MilliValue() > 0 {
+		percent = quantity.MilliValue() * 100 / total.MilliValue()
+	}
+	return fmt.Sprintf("%s (%d%%)", quantity.String(), percent)
+}
+
+func describeNamespace(client kubernetes.Interface, name string, opts describeOptions) error {
+	namespace, err := client.CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "namespace", name, "")
+	}
+
+	return opts.print(namespace, func(d *output.DescribeWriter) {
+		describeNamespaceText(d, client, namespace)
+	})
+}
+
+func describeNamespaceText(d *output.DescribeWriter, client kubernetes.Interface, namespace *corev1.Namespace) {
+	describeMetadata(d, namespace)
+	d.Field(0, "Status", output.ColorizeStatus(string(namespace.Status.Phase)))
+	describeResourceQuotas(d, client, namespace.Name)
+	describeLimitRanges(d, client, namespace.Name)
+}
+
+func describeResourceQuotas(d *output.DescribeWriter, client kubernetes.Interface, namespace string) {
+	quotas, err := client.CoreV1().ResourceQuotas(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		d.Field(0, "Resource Quotas", fmt.Sprintf("<unable to list resource quotas: %v>", err))
+		return
+	}
+	if len(quotas.Items) == 0 {
+		d.Field(0, "Resource Quotas", "")
+		return
+	}
+
+	d.Section(0, "Resource Quotas")
+	for i := range quotas.Items {
+		quota := &quotas.Items[i]
+		d.Field(1, "Name", quota.Name)
+		d.Row(1, "Resource", "Used", "Hard")
+		d.Row(1, "--------", "----", "----")
+		for _, name := range sortedResourceNames(quota.Status.Hard) {
+			hard := quota.Status.Hard[name]
+			used := quota.Status.Used[name]
+			d.Row(1, string(name), used.String(), hard.String())
+		}
+	}
+}
+
+func describeLimitRanges(d *output.DescribeWriter, client kubernetes.Interface, namespace string) {
+	limitRanges, err := client.CoreV1().LimitRanges(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		d.Field(0, "Resource Limits", fmt.Sprintf("<unable to list limit ranges: %v>", err))
+		return
+	}
+	if len(limitRanges.Items) == 0 {
+		d.Field(0, "Resource Limits", "")
+		return
+	}
+
+	d.Section(0, "Resource Limits")
+	for i := range limitRanges.Items {
+		limitRange := &limitRanges.Items[i]
+		d.Field(1, "Name", limitRange.Name)
+		d.Row(1, "Type", "Resource", "Min", "Max", "Default Request", "Default Limit", "Max Limit/Request Ratio")
+		d.Row(1, "----", "--------", "---", "---", "---------------", "-------------", "-----------------------")
+		for _, item := range limitRange.Spec.Limits {
+			names := map[corev1.ResourceName]bool{}
+			for _, list := range []corev1.ResourceList{item.Min, item.Max, item.DefaultRequest, item.Default, item.MaxLimitRequestRatio} {
+				for name := range list {
+					names[name] = true
+				}
+			}
+			all := corev1.ResourceList{}
+			for name := range names {
+				all[name] = resource.Quantity{}
+			}
+			for _, name := range sortedResourceNames(all) {
+				d.Row(1, string(item.Type), string(name),
+					quantityOrDash(item.Min, name), quantityOrDash(item.Max, name),
+					quantityOrDash(item.DefaultRequest, name), quantityOrDash(item.Default, name),
+					quantityOrDash(item.MaxLimitRequestRatio, name))
+			}
+		}
+	}
+}
+
+func sortedResourceNames(resources corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func quantityOrDash(resources corev1.ResourceList, name corev1.ResourceName) string {
+	if quantity, ok := resources[name]; ok {
+		return quantity.String()
+	}
+	return "-"
+}
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func describeConfigMap(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "configmap", name, namespace)
+	}
+
+	return opts.print(configMap, func(d *output.DescribeWriter) {
+		describeConfigMapText(d, client, configMap)
+	})
+}
+
+func describeConfigMapText(d *output.DescribeWriter, client kubernetes.Interface, configMap *corev1.ConfigMap) {
+	describeMetadata(d, configMap)
+	describeValues(d, "Data", configMap.Data)
+
+	sizes := make(map[string]int, len(configMap.BinaryData))
+	for key, value := range configMap.BinaryData {
+		sizes[key] = len(value)
+	}
+	describeSizes(d, "BinaryData", sizes)
+	describeEvents(d, client, "ConfigMap", configMap)
+}
+
+func describeSecret(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "secret", name, namespace)
+	}
+
+	redacted, err := redactSecret(secret, opts.decode)
+	if err != nil {
+		return err
+	}
+	return opts.print(redacted, func(d *output.DescribeWriter) {
+		describeSecretText(d, client, secret, opts.decode)
+	})
+}
+
+// describeSecretText shows only the size of each value unless decode is set.
+// TLS and registry secrets also get a summary that never includes the private
+// key or passwords.
+func describeSecretText(d *output.DescribeWriter, client kubernetes.Interface, secret *corev1.Secret, decode bool) {
+	describeMetadata(d, secret)
+
+	secretType := string(secret.Type)
+	if secretType == "" {
+		secretType = SecretTypeOpaque
+	}
+	d.Field(0, "Type", secretType)
+
+	describeSecretData(d, secret.Data, decode)
+
+	switch secret.Type {
+	case corev1.SecretTypeTLS:
+		describeCertificate(d, secret.Data[corev1.TLSCertKey])
+	case corev1.SecretTypeDockerConfigJson:
+		describeRegistries(d, secret.Data[corev1.DockerConfigJsonKey], false)
+	case corev1.SecretTypeDockercfg:
+		describeRegistries(d, secret.Data[corev1.DockerConfigKey], true)
+	}
+	describeEvents(d, client, "Secret", secret)
+}
+
+func describeServiceAccount(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	serviceAccount, err := client.CoreV1().ServiceAccounts(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "serviceaccount", name, namespace)
+	}
+
+	return opts.print(serviceAccount, func(d *output.DescribeWriter) {
+		describeServiceAccountText(d, client, serviceAccount)
+	})
+}
+
+func describeServiceAccountText(d *output.DescribeWriter, client kubernetes.Interface, serviceAccount *corev1.ServiceAccount) {
+	describeMetadata(d, serviceAccount)
+
+	pullSecrets := make([]string, 0, len(serviceAccount.ImagePullSecrets))
+	for _, secret := range serviceAccount.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, secret.Name)
+	}
+	describeList(d, 0, "Image pull secrets", pullSecrets)
+
+	mountable := make([]string, 0, len(serviceAccount.Secrets))
+	for _, secret := range serviceAccount.Secrets {
+		mountable = append(mountable, secret.Name)
+	}
+	describeList(d, 0, "Mountable secrets", mountable)
+
+	automount := "true"
+	if serviceAccount.AutomountServiceAccountToken != nil {
+		automount = fmt.Sprintf("%t", *serviceAccount.AutomountServiceAccountToken)
+	}
+	d.Field(0, "Automount token", automount)
+	describeEvents(d, client, "ServiceAccount", serviceAccount)
+}
+
+// describeValues writes each key followed by its value, like kubectl does for
+// ConfigMaps.
+func describeValues(d *output.DescribeWriter, title string, values map[string]string) {
+	if len(values) == 0 {
+		d.Field(0, title, "")
+		return
+	}
+
+	d.Section(0, title)
+	for _, key := range sortedKeys(values) {
+		d.Section(0, key)
+		d.Row(0, "----")
+		for _, line := range strings.Split(strings.TrimRight(values[key], "\n"), "\n") {
+			d.Row(0, line)
+		}
+		d.Row(0, "")
+	}
+}
+
+// describeSizes writes the byte size of each key under a section.
+func describeSizes(d *output.DescribeWriter, title string, sizes map[string]int) {
+	if len(sizes) == 0 {
+		d.Field(0, title, "")
+		return
+	}
+
+	keys := make([]string, 0, len(sizes))
+	for key := range sizes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	d.Section(0, title)
+	for _, key := range keys {
+		d.Field(1, key, fmt.Sprintf("%d bytes", sizes[key]))
+	}
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
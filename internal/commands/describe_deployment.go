@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// revisionAnnotation is set by the deployment controller on each ReplicaSet
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+func describeDeployment(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "deployment", name, namespace)
+	}
+
+	return opts.print(deployment, func(d *output.DescribeWriter) {
+		describeDeploymentText(d, client, deployment)
+	})
+}
+
+func describeDeploymentText(d *output.DescribeWriter, client kubernetes.Interface, deployment *appsv1.Deployment) {
+	describeMetadata(d, deployment)
+	d.Field(0, "Selector", metav1.FormatLabelSelector(deployment.Spec.Selector))
+
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	d.Field(0, "Replicas", fmt.Sprintf("%d desired | %d updated | %d total | %d available | %d unavailable",
+		desired, deployment.Status.UpdatedReplicas, deployment.Status.Replicas,
+		deployment.Status.AvailableReplicas, deployment.Status.UnavailableReplicas))
+	d.Field(0, "StrategyType", string(deployment.Spec.Strategy.Type))
+	d.Field(0, "MinReadySeconds", fmt.Sprintf("%d", deployment.Spec.MinReadySeconds))
+	if rollingUpdate := deployment.Spec.Strategy.RollingUpdate; rollingUpdate != nil &&
+		rollingUpdate.MaxUnavailable != nil && rollingUpdate.MaxSurge != nil {
+		d.Field(0, "RollingUpdateStrategy", fmt.Sprintf("%s max unavailable, %s max surge",
+			rollingUpdate.MaxUnavailable.String(), rollingUpdate.MaxSurge.String()))
+	}
+
+	describePodTemplate(d, deployment.Spec.Template)
+
+	if len(deployment.Status.Conditions) > 0 {
+		d.Section(0, "Conditions")
+		d.Row(1, "Type", "Status", "Reason")
+		d.Row(1, "----", "------", "------")
+		for _, condition := range deployment.Status.Conditions {
+			d.Row(1, string(condition.Type), colorizeConditionStatus(condition.Status), condition.Reason)
+		}
+	}
+
+	describeReplicaSets(d, client, deployment)
+	describeEvents(d, client, "Deployment", deployment)
+}
+
+// describeReplicaSets lists the ReplicaSets owned by a deployment, newest
+// revision first.
+func describeReplicaSets(d *output.DescribeWriter, client kubernetes.Interface, deployment *appsv1.Deployment) {
+	selector := metav1.FormatLabelSelector(deployment.Spec.Selector)
+	if selector == NoneValue {
+		selector = ""
+	}
+	list, err := client.AppsV1().ReplicaSets(deployment.Namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		d.Field(0, "ReplicaSets", fmt.Sprintf("<unable to list replicasets: %v>", err))
+		return
+	}
+
+	owned := []*appsv1.ReplicaSet{}
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], deployment) {
+			owned = append(owned, &list.Items[i])
+		}
+	}
+	if len(owned) == 0 {
+		d.Field(0, "ReplicaSets", "")
+		return
+	}
+
+	sort.SliceStable(owned, func(i, j int) bool {
+		return revision(owned[i]) > revision(owned[j])
+	})
+
+	d.Section(0, "ReplicaSets")
+	d.Row(1, "Name", "Revision", "Desired", "Current", "Ready", "Age")
+	d.Row(1, "----", "--------", "-------", "-------", "-----", "---")
+	for _, rs := range owned {
+		desired := int32(0)
+		if rs.Spec.Replicas != nil {
+			desired = *rs.Spec.Replicas
+		}
+		d.Row(1, rs.Name, valueOrNone(rs.Annotations[revisionAnnotation]),
+			fmt.Sprintf("%d", desired), fmt.Sprintf("%d", rs.Status.Replicas),
+			fmt.Sprintf("%d", rs.Status.ReadyReplicas), getAge(rs.CreationTimestamp))
+	}
+}
+
+func revision(rs *appsv1.ReplicaSet) int {
+	var value int
+	_, _ = fmt.Sscan(rs.Annotations[revisionAnnotation], &value)
+	return value
+}
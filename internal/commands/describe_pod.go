@@ -0,0 +1,278 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func describePod(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	pod, err := client.CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "pod", name, namespace)
+	}
+
+	return opts.print(pod, func(d *output.DescribeWriter) {
+		describePodText(d, client, pod)
+	})
+}
+
+func describePodText(d *output.DescribeWriter, client kubernetes.Interface, pod *corev1.Pod) {
+	describeMetadata(d, pod)
+	if pod.Spec.Priority != nil {
+		d.Field(0, "Priority", fmt.Sprintf("%d", *pod.Spec.Priority))
+	}
+	d.Field(0, "Service Account", pod.Spec.ServiceAccountName)
+	node := pod.Spec.NodeName
+	if node != "" && pod.Status.HostIP != "" {
+		node += "/" + pod.Status.HostIP
+	}
+	d.Field(0, "Node", node)
+	if pod.Status.StartTime != nil {
+		d.Field(0, "Start Time", formatTimestamp(*pod.Status.StartTime))
+	}
+	d.Field(0, "Status", output.ColorizeStatus(getPodStatus(pod)))
+	if pod.Status.Reason != "" {
+		d.Field(0, "Reason", pod.Status.Reason)
+	}
+	if pod.Status.Message != "" {
+		d.Field(0, "Message", pod.Status.Message)
+	}
+	d.Field(0, "IP", pod.Status.PodIP)
+
+	statuses := map[string]*corev1.ContainerStatus{}
+	for _, list := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for i := range list {
+			statuses[list[i].Name] = &list[i]
+		}
+	}
+	if len(pod.Spec.InitContainers) > 0 {
+		describeContainers(d, "Init Containers", pod.Spec.InitContainers, statuses)
+	}
+	describeContainers(d, "Containers", pod.Spec.Containers, statuses)
+
+	if len(pod.Status.Conditions) > 0 {
+		d.Section(0, "Conditions")
+		d.Row(1, "Type", "Status")
+		for _, condition := range pod.Status.Conditions {
+			d.Row(1, string(condition.Type), colorizeConditionStatus(condition.Status))
+		}
+	}
+
+	describeVolumes(d, 0, pod.Spec.Volumes)
+	d.Field(0, "QoS Class", string(pod.Status.QOSClass))
+	describeMap(d, 0, "Node-Selectors", pod.Spec.NodeSelector)
+	describeTolerations(d, pod.Spec.Tolerations)
+	describeEvents(d, client, "Pod", pod)
+}
+
+// describePodTemplate writes the pod template of a workload controller.
+func describePodTemplate(d *output.DescribeWriter, template corev1.PodTemplateSpec) {
+	d.Section(0, "Pod Template")
+	describeMap(d, 1, "Labels", template.Labels)
+	d.Field(1, "Service Account", template.Spec.ServiceAccountName)
+	if len(template.Spec.InitContainers) > 0 {
+		describeContainers(d, "Init Containers", template.Spec.InitContainers, nil)
+	}
+	describeContainers(d, "Containers", template.Spec.Containers, nil)
+	describeVolumes(d, 1, template.Spec.Volumes)
+}
+
+// describeContainers writes each container's spec and, when statuses has an
+// entry for it, its current and last state. Pod templates pass no statuses.
+func describeContainers(d *output.DescribeWriter, title string, containers []corev1.Container, statuses map[string]*corev1.ContainerStatus) {
+	if len(containers) == 0 {
+		d.Field(0, title, "")
+		return
+	}
+
+	indent := 1
+	if statuses == nil {
+		indent = 2
+	}
+	d.Section(indent-1, title)
+	for i := range containers {
+		container := &containers[i]
+		d.Section(indent, container.Name)
+		status := statuses[container.Name]
+		if status != nil && status.ContainerID != "" {
+			d.Field(indent+1, "Container ID", status.ContainerID)
+		}
+		d.Field(indent+1, "Image", container.Image)
+		if status != nil && status.ImageID != "" {
+			d.Field(indent+1, "Image ID", status.ImageID)
+		}
+
+		ports := make([]string, 0, len(container.Ports))
+		for _, port := range container.Ports {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.ContainerPort, port.Protocol))
+		}
+		d.Field(indent+1, "Port", strings.Join(ports, ", "))
+		if len(container.Command) > 0 {
+			describeList(d, indent+1, "Command", container.Command)
+		}
+		if len(container.Args) > 0 {
+			describeList(d, indent+1, "Args", container.Args)
+		}
+
+		if status != nil {
+			describeContainerState(d, indent+1, "State", status.State)
+			if status.LastTerminationState.Terminated != nil {
+				describeContainerState(d, indent+1, "Last State", status.LastTerminationState)
+			}
+			d.Field(indent+1, "Ready", fmt.Sprintf("%t", status.Ready))
+			d.Field(indent+1, "Restart Count", fmt.Sprintf("%d", status.RestartCount))
+		}
+
+		describeResources(d, indent+1, "Limits", container.Resources.Limits)
+		describeResources(d, indent+1, "Requests", container.Resources.Requests)
+		describeEnvironment(d, indent+1, container.Env)
+
+		mounts := make([]string, 0, len(container.VolumeMounts))
+		for _, mount := range container.VolumeMounts {
+			access := "rw"
+			if mount.ReadOnly {
+				access = "ro"
+			}
+			mounts = append(mounts, fmt.Sprintf("%s from %s (%s)", mount.MountPath, mount.Name, access))
+		}
+		describeList(d, indent+1, "Mounts", mounts)
+	}
+}
+
+func describeContainerState(d *output.DescribeWriter, indent int, label string, state corev1.ContainerState) {
+	switch {
+	case state.Running != nil:
+		d.Field(indent, label, output.ColorizeStatus("Running"))
+		d.Field(indent+1, "Started", formatTimestamp(state.Running.StartedAt))
+	case state.Waiting != nil:
+		d.Field(indent, label, output.StatusPending.Sprint("Waiting"))
+		d.Field(indent+1, "Reason", output.ColorizeStatus(state.Waiting.Reason))
+		if state.Waiting.Message != "" {
+			d.Field(indent+1, "Message", state.Waiting.Message)
+		}
+	case state.Terminated != nil:
+		d.Field(indent, label, "Terminated")
+		d.Field(indent+1, "Reason", output.ColorizeStatus(state.Terminated.Reason))
+		if state.Terminated.Message != "" {
+			d.Field(indent+1, "Message", state.Terminated.Message)
+		}
+		d.Field(indent+1, "Exit Code", fmt.Sprintf("%d", state.Terminated.ExitCode))
+		d.Field(indent+1, "Started", formatTimestamp(state.Terminated.StartedAt))
+		d.Field(indent+1, "Finished", formatTimestamp(state.Terminated.FinishedAt))
+	default:
+		d.Field(indent, label, "Waiting")
+	}
+}
+
+func describeResources(d *output.DescribeWriter, indent int, label string, resources corev1.ResourceList) {
+	if len(resources) == 0 {
+		return
+	}
+	d.Section(indent, label)
+	for _, name := range sortedResourceNames(resources) {
+		quantity := resources[name]
+		d.Field(indent+1, string(name), quantity.String())
+	}
+}
+
+func describeEnvironment(d *output.DescribeWriter, indent int, env []corev1.EnvVar) {
+	if len(env) == 0 {
+		d.Field(indent, "Environment", "")
+		return
+	}
+
+	d.Section(indent, "Environment")
+	for _, variable := range env {
+		value := variable.Value
+		if from := variable.ValueFrom; from != nil {
+			switch {
+			case from.SecretKeyRef != nil:
+				value = fmt.Sprintf("<set to the key '%s' in secret '%s'>", from.SecretKeyRef.Key, from.SecretKeyRef.Name)
+			case from.ConfigMapKeyRef != nil:
+				value = fmt.Sprintf("<set to the key '%s' of config map '%s'>", from.ConfigMapKeyRef.Key, from.ConfigMapKeyRef.Name)
+			case from.FieldRef != nil:
+				value = fmt.Sprintf("(%s:%s)", from.FieldRef.APIVersion, from.FieldRef.FieldPath)
+			case from.ResourceFieldRef != nil:
+				value = fmt.Sprintf("%s (limits/requests)", from.ResourceFieldRef.Resource)
+			}
+		}
+		d.Field(indent+1, variable.Name, value)
+	}
+}
+
+// describeVolumes writes each volume with a short description of its source.
+func describeVolumes(d *output.DescribeWriter, indent int, volumes []corev1.Volume) {
+	if len(volumes) == 0 {
+		d.Field(indent, "Volumes", "")
+		return
+	}
+
+	d.Section(indent, "Volumes")
+	for _, volume := range volumes {
+		d.Section(indent+1, volume.Name)
+		source := volume.VolumeSource
+		switch {
+		case source.ConfigMap != nil:
+			d.Field(indent+2, "Type", "ConfigMap (a volume populated by a ConfigMap)")
+			d.Field(indent+2, "Name", source.ConfigMap.Name)
+		case source.Secret != nil:
+			d.Field(indent+2, "Type", "Secret (a volume populated by a Secret)")
+			d.Field(indent+2, "SecretName", source.Secret.SecretName)
+		case source.PersistentVolumeClaim != nil:
+			d.Field(indent+2, "Type", "PersistentVolumeClaim (a reference to a PersistentVolumeClaim in the same namespace)")
+			d.Field(indent+2, "ClaimName", source.PersistentVolumeClaim.ClaimName)
+			d.Field(indent+2, "ReadOnly", fmt.Sprintf("%t", source.PersistentVolumeClaim.ReadOnly))
+		case source.EmptyDir != nil:
+			d.Field(indent+2, "Type", "EmptyDir (a temporary directory that shares a pod's lifetime)")
+			d.Field(indent+2, "Medium", string(source.EmptyDir.Medium))
+		case source.HostPath != nil:
+			d.Field(indent+2, "Type", "HostPath (bare host directory volume)")
+			d.Field(indent+2, "Path", source.HostPath.Path)
+		case source.Projected != nil:
+			d.Field(indent+2, "Type", "Projected (a volume that contains injected data from multiple sources)")
+		case source.DownwardAPI != nil:
+			d.Field(indent+2, "Type", "DownwardAPI (a volume populated by information about the pod)")
+		default:
+			d.Field(indent+2, "Type", "<unknown>")
+		}
+	}
+}
+
+func describeTolerations(d *output.DescribeWriter, tolerations []corev1.Toleration) {
+	values := make([]string, 0, len(tolerations))
+	for _, toleration := range tolerations {
+		value := toleration.Key
+		if toleration.Value != "" {
+			value += "=" + toleration.Value
+		}
+		if toleration.Effect != "" {
+			value += ":" + string(toleration.Effect)
+		}
+		if toleration.Operator == corev1.TolerationOpExists && toleration.Value == "" {
+			value += " op=Exists"
+		}
+		if toleration.TolerationSeconds != nil {
+			value += fmt.Sprintf(" for %ds", *toleration.TolerationSeconds)
+		}
+		values = append(values, strings.TrimSpace(value))
+	}
+	describeList(d, 0, "Tolerations", values)
+}
+
+func colorizeConditionStatus(status corev1.ConditionStatus) string {
+	switch status {
+	case corev1.ConditionTrue:
+		return output.StatusRunning.Sprint(status)
+	case corev1.ConditionFalse:
+		return output.StatusError.Sprint(status)
+	default:
+		return output.StatusWarning.Sprint(status)
+	}
+}
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxEndpoints caps the addresses printed per service port
+const maxEndpoints = 3
+
+func describeService(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	service, err := client.CoreV1().Services(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "service", name, namespace)
+	}
+
+	return opts.print(service, func(d *output.DescribeWriter) {
+		describeServiceText(d, client, service)
+	})
+}
+
+func describeServiceText(d *output.DescribeWriter, client kubernetes.Interface, service *corev1.Service) {
+	describeMetadata(d, service)
+	describeMap(d, 0, "Selector", service.Spec.Selector)
+	d.Field(0, "Type", string(service.Spec.Type))
+	d.Field(0, "IP", service.Spec.ClusterIP)
+	if len(service.Spec.ExternalIPs) > 0 {
+		d.Field(0, "External IPs", strings.Join(service.Spec.ExternalIPs, ","))
+	}
+	if service.Spec.ExternalName != "" {
+		d.Field(0, "External Name", service.Spec.ExternalName)
+	}
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		address := ingress.IP
+		if address == "" {
+			address = ingress.Hostname
+		}
+		d.Field(0, "LoadBalancer Ingress", address)
+	}
+
+	// A missing Endpoints object just means nothing is ready yet
+	endpoints, err := client.CoreV1().Endpoints(service.Namespace).Get(context.Background(), service.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		endpoints, err = nil, nil
+	} else if err != nil {
+		d.Field(0, "Endpoints", fmt.Sprintf("<unable to get endpoints: %v>", err))
+	}
+
+	for _, port := range service.Spec.Ports {
+		portName := port.Name
+		if portName == "" {
+			portName = "<unset>"
+		}
+		d.Field(0, "Port", fmt.Sprintf("%s  %d/%s", portName, port.Port, port.Protocol))
+		d.Field(0, "TargetPort", fmt.Sprintf("%s/%s", port.TargetPort.String(), port.Protocol))
+		if port.NodePort != 0 {
+			d.Field(0, "NodePort", fmt.Sprintf("%s  %d/%s", portName, port.NodePort, port.Protocol))
+		}
+		if err == nil {
+			d.Field(0, "Endpoints", formatEndpoints(endpoints, port.Name))
+		}
+	}
+	d.Field(0, "Session Affinity", string(service.Spec.SessionAffinity))
+	describeEvents(d, client, "Service", service)
+}
+
+// formatEndpoints lists the ready addresses serving a port, like kubectl.
+func formatEndpoints(endpoints *corev1.Endpoints, portName string) string {
+	if endpoints == nil {
+		return ""
+	}
+
+	addresses := []string{}
+	total := 0
+	for _, subset := range endpoints.Subsets {
+		for _, port := range subset.Ports {
+			if port.Name != portName {
+				continue
+			}
+			for _, address := range subset.Addresses {
+				total++
+				if len(addresses) < maxEndpoints {
+					addresses = append(addresses, fmt.Sprintf("%s:%d", address.IP, port.Port))
+				}
+			}
+		}
+	}
+
+	result := strings.Join(addresses, ",")
+	if total > maxEndpoints {
+		result += fmt.Sprintf(" + %d more...", total-maxEndpoints)
+	}
+	return result
+}
+
+func describeIngress(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	ingress, err := client.NetworkingV1().Ingresses(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "ingress", name, namespace)
+	}
+
+	return opts.print(ingress, func(d *output.DescribeWriter) {
+		describeIngressText(d, client, ingress)
+	})
+}
+
+func describeIngressText(d *output.DescribeWriter, client kubernetes.Interface, ingress *networkingv1.Ingress) {
+	describeMetadata(d, ingress)
+
+	class := ""
+	if ingress.Spec.IngressClassName != nil {
+		class = *ingress.Spec.IngressClassName
+	}
+	d.Field(0, "Ingress Class", class)
+
+	addresses := []string{}
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
+		}
+	}
+	d.Field(0, "Address", strings.Join(addresses, ","))
+	d.Field(0, "Default backend", formatIngressBackend(ingress.Spec.DefaultBackend))
+
+	tls := make([]string, 0, len(ingress.Spec.TLS))
+	for _, entry := range ingress.Spec.TLS {
+		tls = append(tls, fmt.Sprintf("%s terminates %s", valueOrNone(entry.SecretName), strings.Join(entry.Hosts, ",")))
+	}
+	if len(tls) > 0 {
+		describeList(d, 0, "TLS", tls)
+	}
+
+	d.Section(0, "Rules")
+	d.Row(1, "Host", "Path", "Backends")
+	d.Row(1, "----", "----", "--------")
+	for _, rule := range ingress.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		if rule.HTTP == nil {
+			d.Row(1, host, "", formatIngressBackend(ingress.Spec.DefaultBackend))
+			continue
+		}
+		for i := range rule.HTTP.Paths {
+			path := rule.HTTP.Paths[i]
+			d.Row(1, host, valueOrNone(path.Path), formatIngressBackend(&path.Backend))
+		}
+	}
+	describeEvents(d, client, "Ingress", ingress)
+}
+
+func formatIngressBackend(backend *networkingv1.IngressBackend) string {
+	switch {
+	case backend == nil:
+		return NoneValue
+	case backend.Service != nil:
+		port := backend.Service.Port.Name
+		if port == "" {
+			port = fmt.Sprintf("%d", backend.Service.Port.Number)
+		}
+		return fmt.Sprintf("%s:%s", backend.Service.Name, port)
+	case backend.Resource != nil:
+		return fmt.Sprintf("%s/%s", backend.Resource.Kind, backend.Resource.Name)
+	default:
+		return NoneValue
+	}
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func describePersistentVolumeClaim(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	claim, err := client.CoreV1().PersistentVolumeClaims(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "persistentvolumeclaim", name, namespace)
+	}
+
+	return opts.print(claim, func(d *output.DescribeWriter) {
+		describePersistentVolumeClaimText(d, client, claim)
+	})
+}
+
+func describePersistentVolumeClaimText(d *output.DescribeWriter, client kubernetes.Interface, claim *corev1.PersistentVolumeClaim) {
+	describeMetadata(d, claim)
+	d.Field(0, "StorageClass", stringValue(claim.Spec.StorageClassName))
+	d.Field(0, "Status", output.ColorizeStatus(string(claim.Status.Phase)))
+	d.Field(0, "Volume", claim.Spec.VolumeName)
+
+	capacity := ""
+	if storage, ok := claim.Status.Capacity[corev1.ResourceStorage]; ok {
+		capacity = storage.String()
+	}
+	d.Field(0, "Capacity", capacity)
+	d.Field(0, "Access Modes", formatAccessModes(claim.Status.AccessModes))
+	if claim.Spec.VolumeMode != nil {
+		d.Field(0, "VolumeMode", string(*claim.Spec.VolumeMode))
+	}
+	d.Field(0, "Used By", strings.Join(claimUsers(client, claim), ", "))
+	describeEvents(d, client, "PersistentVolumeClaim", claim)
+}
+
+// claimUsers lists the pods in the claim's namespace that mount it.
+func claimUsers(client kubernetes.Interface, claim *corev1.PersistentVolumeClaim) []string {
+	pods, err := client.CoreV1().Pods(claim.Namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return []string{fmt.Sprintf("<unable to list pods: %v>", err)}
+	}
+
+	users := []string{}
+	for i := range pods.Items {
+		for _, volume := range pods.Items[i].Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claim.Name {
+				users = append(users, pods.Items[i].Name)
+				break
+			}
+		}
+	}
+	return users
+}
+
+// formatAccessModes uses the abbreviations kubectl prints, e.g. "RWO,ROX".
+func formatAccessModes(modes []corev1.PersistentVolumeAccessMode) string {
+	short := map[corev1.PersistentVolumeAccessMode]string{
+		corev1.ReadWriteOnce:    "RWO",
+		corev1.ReadOnlyMany:     "ROX",
+		corev1.ReadWriteMany:    "RWX",
+		corev1.ReadWriteOncePod: "RWOP",
+	}
+	result := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		if abbreviation, ok := short[mode]; ok {
+			result = append(result, abbreviation)
+		} else {
+			result = append(result, string(mode))
+		}
+	}
+	return strings.Join(result, ",")
+}
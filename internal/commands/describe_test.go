@@ -0,0 +1,360 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func assertContains(t *testing.T, result string, expected ...string) {
+	t.Helper()
+	for _, s := range expected {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expected %q in output, got:\n%s", s, result)
+		}
+	}
+}
+
+func TestDescribePod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "pod-uid", Labels: map[string]string{"app": "web"}},
+		Spec: corev1.PodSpec{
+			NodeName:    "node-1",
+			Containers:  []corev1.Container{{Name: "app", Image: "nginx:1.25"}},
+			Volumes:     []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}}}}},
+			Tolerations: []corev1.Toleration{{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "app",
+				RestartCount:         4,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			}},
+		},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web", Namespace: "default", UID: "pod-uid"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Source:         corev1.EventSource{Component: "kubelet"},
+	}
+	clientset := fake.NewSimpleClientset(pod, event)
+
+	var buf bytes.Buffer
+	if err := describePod(clientset, "default", "web", describeOptions{out: &buf}); err != nil {
+		t.Fatalf("describePod failed: %v", err)
+	}
+
+	assertContains(t, buf.String(),
+		"Name:", "app=web", "node-1",
+		"Image:", "nginx:1.25",
+		"CrashLoopBackOff", "Last State:", "OOMKilled", "Exit Code:", "137", "Restart Count:", "4",
+		"Conditions:", "Ready",
+		"Volumes:", "web-config",
+		"Tolerations:", "node.kubernetes.io/not-ready:NoExecute op=Exists",
+		"Events:", "BackOff", "Back-off restarting failed container", "kubelet",
+	)
+}
+
+func TestDescribePodStructuredOutput(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}})
+
+	var buf bytes.Buffer
+	if err := describePod(clientset, "default", "web", describeOptions{outputFormat: "yaml", out: &buf}); err != nil {
+		t.Fatalf("describePod failed: %v", err)
+	}
+	assertContains(t, buf.String(), "kind: Pod", "name: web")
+}
+
+func TestDescribeDeployment(t *testing.T) {
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", UID: "deploy-uid"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Image: "api:2"}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"}},
+		},
+	}
+	controller := true
+	owner := []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "api", UID: "deploy-uid", Controller: &controller}}
+	replicaSet := func(name, revision string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			Labels:          map[string]string{"app": "api"},
+			Annotations:     map[string]string{revisionAnnotation: revision},
+			OwnerReferences: owner,
+		}}
+	}
+	orphan := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "default", Labels: map[string]string{"app": "api"}}}
+	clientset := fake.NewSimpleClientset(deployment, replicaSet("api-old", "1"), replicaSet("api-new", "2"), orphan)
+
+	var buf bytes.Buffer
+	if err := describeDeployment(clientset, "default", "api", describeOptions{out: &buf}); err != nil {
+		t.Fatalf("describeDeployment failed: %v", err)
+	}
+
+	result := buf.String()
+	assertContains(t, result, "Selector:", "app=api", "2 desired", "Pod Template:", "api:2", "Progressing", "NewReplicaSetAvailable", "ReplicaSets:")
+	if strings.Index(result, "api-new") > strings.Index(result, "api-old") {
+		t.Errorf("Expected the newest ReplicaSet first, got:\n%s", result)
+	}
+	if strings.Contains(result, "orphan") {
+		t.Errorf("ReplicaSets not owned by the deployment should be skipped, got:\n%s", result)
+	}
+}
+
+func TestDescribeService(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: "10.96.0.10",
+			Selector:  map[string]string{"app": "api"},
+			Ports:     []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080), Protocol: corev1.ProtocolTCP}},
+		},
+	}
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}, {IP: "10.0.0.3"}, {IP: "10.0.0.4"}},
+			Ports:     []corev1.EndpointPort{{Name: "http", Port: 8080}},
+		}},
+	}
+	clientset := fake.NewSimpleClientset(service, endpoints)
+
+	var buf bytes.Buffer
+	if err := describeService(clientset, "default", "api", describeOptions{out: &buf}); err != nil {
+		t.Fatalf("describeService failed: %v", err)
+	}
+	assertContains(t, buf.String(), "10.96.0.10", "http  80/TCP", "8080/TCP", "10.0.0.1:8080,10.0.0.2:8080,10.0.0.3:8080 + 1 more...")
+}
+
+func TestDescribeServiceWithoutEndpoints(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080), Protocol: corev1.ProtocolTCP},
+			{Name: "grpc", Port: 9090, TargetPort: intstr.FromInt(9090), Protocol: corev1.ProtocolTCP},
+		}},
+	}
+	clientset := fake.NewSimpleClientset(service)
+
+	var buf bytes.Buffer
+	if err := describeService(clientset, "default", "api", describeOptions{out: &buf}); err != nil {
+		t.Fatalf("describeService failed: %v", err)
+	}
+	none := 0
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "Endpoints:" && fields[1] == NoneValue {
+			none++
+		}
+	}
+	if none != 2 {
+		t.Errorf("Expected one 'Endpoints: <none>' line per port, got:\n%s", buf.String())
+	}
+}
+
+func TestDescribeSecretHidesValues(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	})
+
+	var buf bytes.Buffer
+	if err := describeSecret(clientset, "default", "creds", describeOptions{out: &buf}); err != nil {
+		t.Fatalf("describeSecret failed: %v", err)
+	}
+	assertContains(t, buf.String(), "password:", "7 bytes")
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("Secret values must not be printed, got:\n%s", buf.String())
+	}
+}
+
+func TestDescribeNode(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"node-role.kubernetes.io/worker": ""}},
+		Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue, Reason: "KubeletReady"}},
+		},
+	}
+	pod := func(name, cpu, memory string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{
+				NodeName: "node-1",
+				Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+				}}},
+			},
+		}
+	}
+	node.UID = "3f1c2a9e-node-uid"
+	clientset := fake.NewSimpleClientset(node, pod("a", "500m", "1Gi"), pod("b", "250m", "1Gi"))
+
+	var buf bytes.Buffer
+	if err := describeNode(clientset, "node-1", describeOptions{out: &buf}); err != nil {
+		t.Fatalf("describeNode failed: %v", err)
+	}
+	assertContains(t, buf.String(),
+		"Roles:", "worker", "dedicated=gpu:NoSchedule", "KubeletReady",
+		"Non-terminated Pods (2 in total)", "500m (25%)",
+		"Allocated resources:", "750m (37%)", "2Gi (50%)",
+	)
+
+	// The kubelet uses the node name as the UID of node events
+	for _, action := range clientset.Actions() {
+		list, ok := action.(k8stesting.ListAction)
+		if !ok || action.GetResource().Resource != "events" {
+			continue
+		}
+		selector := list.GetListRestrictions().Fields
+		if !selector.Matches(fields.Set{
+			"involvedObject.kind": "Node", "involvedObject.name": "node-1",
+			"involvedObject.namespace": "", "involvedObject.uid": "node-1",
+		}) {
+			t.Errorf("Expected node events to be selected by the node name as UID, got %s", selector)
+		}
+		return
+	}
+	t.Error("Expected the node events to be listed")
+}
+
+func TestPodRequestsAndLimits(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		}}},
+		Containers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("300m")}}},
+			{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")}}},
+		},
+		Overhead: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+	}}
+
+	requests, _ := podRequestsAndLimits(pod)
+	cpu := requests[corev1.ResourceCPU]
+	// The init container needs more than the app containers combined
+	if cpu.String() != "1100m" {
+		t.Errorf("Expected 1100m of CPU requested, got %s", cpu.String())
+	}
+}
+
+func TestDescribeNamespace(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "team-a"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("3")},
+			},
+		},
+		&corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "team-a"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type:    corev1.LimitTypeContainer,
+				Default: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}}},
+		},
+	)
+
+	var buf bytes.Buffer
+	if err := describeNamespace(clientset, "team-a", describeOptions{out: &buf}); err != nil {
+		t.Fatalf("describeNamespace failed: %v", err)
+	}
+	assertContains(t, buf.String(), "Resource Quotas:", "compute", "pods", "3", "10", "Resource Limits:", "defaults", "Container", "512Mi")
+}
+
+func TestDescribeStatefulSet(t *testing.T) {
+	replicas := int32(1)
+	storageClass := "fast"
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: "sts-uid"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &storageClass,
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources:        corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}},
+				},
+			}},
+		},
+	}
+	controller := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "db-0", Namespace: "default", Labels: map[string]string{"app": "db"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", UID: "sts-uid", Controller: &controller}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	clientset := fake.NewSimpleClientset(statefulSet, pod)
+
+	var buf bytes.Buffer
+	if err := describeStatefulSet(clientset, "default", "db", describeOptions{out: &buf}); err != nil {
+		t.Fatalf("describeStatefulSet failed: %v", err)
+	}
+	assertContains(t, buf.String(), "1 desired", "1 Running / 0 Waiting", "Volume Claims:", "fast", "10Gi", "RWO")
+}
+
+func TestFormatHPAMetric(t *testing.T) {
+	utilization := int32(80)
+	currentUtilization := int32(45)
+	average := resource.MustParse("90m")
+	metric := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name:   corev1.ResourceCPU,
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &utilization},
+		},
+	}
+	current := &autoscalingv2.MetricStatus{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricStatus{
+			Name:    corev1.ResourceCPU,
+			Current: autoscalingv2.MetricValueStatus{AverageUtilization: &currentUtilization, AverageValue: &average},
+		},
+	}
+
+	label, value := formatHPAMetric(metric, current)
+	if label != "resource cpu on pods (as a percentage of request)" || value != "45% (90m) / 80%" {
+		t.Errorf("formatHPAMetric() = %q, %q", label, value)
+	}
+
+	_, value = formatHPAMetric(metric, nil)
+	if value != "<unknown> / 80%" {
+		t.Errorf("Expected an unknown current value, got %q", value)
+	}
+}
@@ -0,0 +1,356 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+)
+
+func describeStatefulSet(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "statefulset", name, namespace)
+	}
+
+	return opts.print(statefulSet, func(d *output.DescribeWriter) {
+		describeStatefulSetText(d, client, statefulSet)
+	})
+}
+
+func describeStatefulSetText(d *output.DescribeWriter, client kubernetes.Interface, statefulSet *appsv1.StatefulSet) {
+	describeMetadata(d, statefulSet)
+	d.Field(0, "Selector", metav1.FormatLabelSelector(statefulSet.Spec.Selector))
+
+	desired := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		desired = *statefulSet.Spec.Replicas
+	}
+	d.Field(0, "Replicas", fmt.Sprintf("%d desired | %d total", desired, statefulSet.Status.Replicas))
+	d.Field(0, "Update Strategy", string(statefulSet.Spec.UpdateStrategy.Type))
+	if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		d.Field(1, "Partition", fmt.Sprintf("%d", *rollingUpdate.Partition))
+	}
+	d.Field(0, "Service Name", statefulSet.Spec.ServiceName)
+	describePodsStatus(d, client, statefulSet.Namespace, statefulSet.Spec.Selector, statefulSet)
+	describePodTemplate(d, statefulSet.Spec.Template)
+
+	if len(statefulSet.Spec.VolumeClaimTemplates) == 0 {
+		d.Field(0, "Volume Claims", "")
+	} else {
+		d.Section(0, "Volume Claims")
+		for i := range statefulSet.Spec.VolumeClaimTemplates {
+			claim := &statefulSet.Spec.VolumeClaimTemplates[i]
+			storage := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+			d.Field(1, "Name", claim.Name)
+			d.Field(1, "StorageClass", stringValue(claim.Spec.StorageClassName))
+			d.Field(1, "Capacity", storage.String())
+			d.Field(1, "Access Modes", formatAccessModes(claim.Spec.AccessModes))
+		}
+	}
+	describeEvents(d, client, "StatefulSet", statefulSet)
+}
+
+func describeDaemonSet(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "daemonset", name, namespace)
+	}
+
+	return opts.print(daemonSet, func(d *output.DescribeWriter) {
+		describeDaemonSetText(d, client, daemonSet)
+	})
+}
+
+func describeDaemonSetText(d *output.DescribeWriter, client kubernetes.Interface, daemonSet *appsv1.DaemonSet) {
+	describeMetadata(d, daemonSet)
+	d.Field(0, "Selector", metav1.FormatLabelSelector(daemonSet.Spec.Selector))
+	describeMap(d, 0, "Node-Selector", daemonSet.Spec.Template.Spec.NodeSelector)
+
+	status := daemonSet.Status
+	d.Field(0, "Desired Number of Nodes Scheduled", fmt.Sprintf("%d", status.DesiredNumberScheduled))
+	d.Field(0, "Current Number of Nodes Scheduled", fmt.Sprintf("%d", status.CurrentNumberScheduled))
+	d.Field(0, "Number of Nodes Scheduled with Up-to-date Pods", fmt.Sprintf("%d", status.UpdatedNumberScheduled))
+	d.Field(0, "Number of Nodes Scheduled with Available Pods", fmt.Sprintf("%d", status.NumberAvailable))
+	d.Field(0, "Number of Nodes Misscheduled", fmt.Sprintf("%d", status.NumberMisscheduled))
+	describePodsStatus(d, client, daemonSet.Namespace, daemonSet.Spec.Selector, daemonSet)
+	describePodTemplate(d, daemonSet.Spec.Template)
+	describeEvents(d, client, "DaemonSet", daemonSet)
+}
+
+// describePodsStatus counts the pods a controller owns by phase.
+func describePodsStatus(d *output.DescribeWriter, client kubernetes.Interface, namespace string, selector *metav1.LabelSelector, owner metav1.Object) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		d.Field(0, "Pods Status", fmt.Sprintf("<invalid selector: %v>", err))
+		return
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		d.Field(0, "Pods Status", fmt.Sprintf("<unable to list pods: %v>", err))
+		return
+	}
+
+	counts := map[corev1.PodPhase]int{}
+	for i := range pods.Items {
+		if metav1.IsControlledBy(&pods.Items[i], owner) {
+			counts[pods.Items[i].Status.Phase]++
+		}
+	}
+	d.Field(0, "Pods Status", fmt.Sprintf("%d Running / %d Waiting / %d Succeeded / %d Failed",
+		counts[corev1.PodRunning], counts[corev1.PodPending], counts[corev1.PodSucceeded], counts[corev1.PodFailed]))
+}
+
+func describeJob(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	job, err := client.BatchV1().Jobs(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "job", name, namespace)
+	}
+
+	return opts.print(job, func(d *output.DescribeWriter) {
+		describeJobText(d, client, job)
+	})
+}
+
+func describeJobText(d *output.DescribeWriter, client kubernetes.Interface, job *batchv1.Job) {
+	describeMetadata(d, job)
+	d.Field(0, "Selector", metav1.FormatLabelSelector(job.Spec.Selector))
+	d.Field(0, "Parallelism", int32Value(job.Spec.Parallelism))
+	d.Field(0, "Completions", int32Value(job.Spec.Completions))
+	if job.Spec.CompletionMode != nil {
+		d.Field(0, "Completion Mode", string(*job.Spec.CompletionMode))
+	}
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		d.Field(0, "Suspend", "true")
+	}
+	d.Field(0, "Backoff Limit", int32Value(job.Spec.BackoffLimit))
+	if job.Spec.ActiveDeadlineSeconds != nil {
+		d.Field(0, "Active Deadline Seconds", fmt.Sprintf("%ds", *job.Spec.ActiveDeadlineSeconds))
+	}
+
+	if start := job.Status.StartTime; start != nil {
+		d.Field(0, "Start Time", formatTimestamp(*start))
+		if completion := job.Status.CompletionTime; completion != nil {
+			d.Field(0, "Completed At", formatTimestamp(*completion))
+			d.Field(0, "Duration", duration.HumanDuration(completion.Sub(start.Time)))
+		}
+	}
+	d.Field(0, "Pods Statuses", fmt.Sprintf("%d Active / %d Succeeded / %d Failed",
+		job.Status.Active, job.Status.Succeeded, job.Status.Failed))
+	describePodTemplate(d, job.Spec.Template)
+
+	if len(job.Status.Conditions) > 0 {
+		d.Section(0, "Conditions")
+		d.Row(1, "Type", "Status", "Reason", "Message")
+		d.Row(1, "----", "------", "------", "-------")
+		for _, condition := range job.Status.Conditions {
+			d.Row(1, string(condition.Type), colorizeConditionStatus(condition.Status), condition.Reason, condition.Message)
+		}
+	}
+	describeEvents(d, client, "Job", job)
+}
+
+func describeCronJob(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	cronJob, err := client.BatchV1().CronJobs(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "cronjob", name, namespace)
+	}
+
+	return opts.print(cronJob, func(d *output.DescribeWriter) {
+		describeCronJobText(d, client, cronJob)
+	})
+}
+
+func describeCronJobText(d *output.DescribeWriter, client kubernetes.Interface, cronJob *batchv1.CronJob) {
+	describeMetadata(d, cronJob)
+	d.Field(0, "Schedule", cronJob.Spec.Schedule)
+	d.Field(0, "Time Zone", stringValue(cronJob.Spec.TimeZone))
+	d.Field(0, "Concurrency Policy", string(cronJob.Spec.ConcurrencyPolicy))
+	suspend := "false"
+	if cronJob.Spec.Suspend != nil {
+		suspend = fmt.Sprintf("%t", *cronJob.Spec.Suspend)
+	}
+	d.Field(0, "Suspend", suspend)
+	d.Field(0, "Successful Job History Limit", int32Value(cronJob.Spec.SuccessfulJobsHistoryLimit))
+	d.Field(0, "Failed Job History Limit", int32Value(cronJob.Spec.FailedJobsHistoryLimit))
+	if cronJob.Spec.StartingDeadlineSeconds != nil {
+		d.Field(0, "Starting Deadline Seconds", fmt.Sprintf("%ds", *cronJob.Spec.StartingDeadlineSeconds))
+	}
+
+	jobTemplate := cronJob.Spec.JobTemplate.Spec
+	d.Field(0, "Parallelism", int32Value(jobTemplate.Parallelism))
+	d.Field(0, "Completions", int32Value(jobTemplate.Completions))
+	describePodTemplate(d, jobTemplate.Template)
+
+	lastSchedule := ""
+	if cronJob.Status.LastScheduleTime != nil {
+		lastSchedule = formatTimestamp(*cronJob.Status.LastScheduleTime)
+	}
+	d.Field(0, "Last Schedule Time", lastSchedule)
+	active := make([]string, 0, len(cronJob.Status.Active))
+	for _, ref := range cronJob.Status.Active {
+		active = append(active, ref.Name)
+	}
+	d.Field(0, "Active Jobs", strings.Join(active, ", "))
+	describeEvents(d, client, "CronJob", cronJob)
+}
+
+func describeHorizontalPodAutoscaler(client kubernetes.Interface, namespace, name string, opts describeOptions) error {
+	hpa, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "horizontalpodautoscaler", name, namespace)
+	}
+
+	return opts.print(hpa, func(d *output.DescribeWriter) {
+		describeHorizontalPodAutoscalerText(d, client, hpa)
+	})
+}
+
+func describeHorizontalPodAutoscalerText(d *output.DescribeWriter, client kubernetes.Interface, hpa *autoscalingv2.HorizontalPodAutoscaler) {
+	describeMetadata(d, hpa)
+	target := hpa.Spec.ScaleTargetRef
+	d.Field(0, "Reference", fmt.Sprintf("%s/%s", target.Kind, target.Name))
+
+	if len(hpa.Spec.Metrics) == 0 {
+		d.Field(0, "Metrics", "")
+	} else {
+		d.Section(0, "Metrics")
+		d.Row(1, "(current / target)")
+		for i, metric := range hpa.Spec.Metrics {
+			var current *autoscalingv2.MetricStatus
+			if i < len(hpa.Status.CurrentMetrics) {
+				current = &hpa.Status.CurrentMetrics[i]
+			}
+			label, value := formatHPAMetric(metric, current)
+			d.Field(1, label, value)
+		}
+	}
+
+	d.Field(0, "Min replicas", int32Value(hpa.Spec.MinReplicas))
+	d.Field(0, "Max replicas", fmt.Sprintf("%d", hpa.Spec.MaxReplicas))
+	d.Field(0, target.Kind+" pods", fmt.Sprintf("%d current / %d desired", hpa.Status.CurrentReplicas, hpa.Status.DesiredReplicas))
+
+	if len(hpa.Status.Conditions) > 0 {
+		d.Section(0, "Conditions")
+		d.Row(1, "Type", "Status", "Reason", "Message")
+		d.Row(1, "----", "------", "------", "-------")
+		for _, condition := range hpa.Status.Conditions {
+			d.Row(1, string(condition.Type), colorizeConditionStatus(condition.Status), condition.Reason, condition.Message)
+		}
+	}
+	describeEvents(d, client, "HorizontalPodAutoscaler", hpa)
+}
+
+// formatHPAMetric describes one metric and its current value, like kubectl,
+// e.g. "resource cpu on pods (as a percentage of request)" and "45% (90m) / 80%".
+func formatHPAMetric(metric autoscalingv2.MetricSpec, current *autoscalingv2.MetricStatus) (label, value string) {
+	switch metric.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if metric.Resource == nil {
+			break
+		}
+		var status *autoscalingv2.MetricValueStatus
+		if current != nil && current.Resource != nil {
+			status = &current.Resource.Current
+		}
+		return formatResourceMetric("resource "+string(metric.Resource.Name)+" on pods", metric.Resource.Target, status)
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if metric.ContainerResource == nil {
+			break
+		}
+		var status *autoscalingv2.MetricValueStatus
+		if current != nil && current.ContainerResource != nil {
+			status = &current.ContainerResource.Current
+		}
+		name := fmt.Sprintf("resource %s of container %q on pods", metric.ContainerResource.Name, metric.ContainerResource.Container)
+		return formatResourceMetric(name, metric.ContainerResource.Target, status)
+	case autoscalingv2.PodsMetricSourceType:
+		if metric.Pods == nil {
+			break
+		}
+		var status *autoscalingv2.MetricValueStatus
+		if current != nil && current.Pods != nil {
+			status = &current.Pods.Current
+		}
+		return fmt.Sprintf("%q on pods", metric.Pods.Metric.Name), formatMetricTarget(metric.Pods.Target, status)
+	case autoscalingv2.ObjectMetricSourceType:
+		if metric.Object == nil {
+			break
+		}
+		var status *autoscalingv2.MetricValueStatus
+		if current != nil && current.Object != nil {
+			status = &current.Object.Current
+		}
+		object := metric.Object.DescribedObject
+		return fmt.Sprintf("%q on %s/%s", metric.Object.Metric.Name, object.Kind, object.Name), formatMetricTarget(metric.Object.Target, status)
+	case autoscalingv2.ExternalMetricSourceType:
+		if metric.External == nil {
+			break
+		}
+		var status *autoscalingv2.MetricValueStatus
+		if current != nil && current.External != nil {
+			status = &current.External.Current
+		}
+		return fmt.Sprintf("%q (external)", metric.External.Metric.Name), formatMetricTarget(metric.External.Target, status)
+	}
+	return fmt.Sprintf("<unknown metric type %q>", metric.Type), ""
+}
+
+func formatResourceMetric(name string, target autoscalingv2.MetricTarget, current *autoscalingv2.MetricValueStatus) (label, value string) {
+	if target.AverageUtilization == nil {
+		return name, formatMetricTarget(target, current)
+	}
+
+	value = "<unknown>"
+	if current != nil && current.AverageUtilization != nil {
+		value = fmt.Sprintf("%d%%", *current.AverageUtilization)
+		if current.AverageValue != nil {
+			value += fmt.Sprintf(" (%s)", current.AverageValue.String())
+		}
+	}
+	return name + " (as a percentage of request)", fmt.Sprintf("%s / %d%%", value, *target.AverageUtilization)
+}
+
+func formatMetricTarget(target autoscalingv2.MetricTarget, current *autoscalingv2.MetricValueStatus) string {
+	value := "<unknown>"
+	if current != nil {
+		switch {
+		case current.Value != nil:
+			value = current.Value.String()
+		case current.AverageValue != nil:
+			value = current.AverageValue.String()
+		}
+	}
+
+	switch {
+	case target.Value != nil:
+		return fmt.Sprintf("%s / %s", value, target.Value.String())
+	case target.AverageValue != nil:
+		return fmt.Sprintf("%s / %s", value, target.AverageValue.String())
+	default:
+		return value
+	}
+}
+
+func int32Value(value *int32) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", *value)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
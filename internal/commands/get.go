@@ -3,6 +3,8 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/robertusnegoro/k8ctl/internal/config"
@@ -13,6 +15,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -32,41 +35,137 @@ var resourceShortcuts = map[string]string{
 	"rs":     "replicasets",
 	"sts":    "statefulsets",
 	"cj":     "cronjobs",
+	"hpa":    "horizontalpodautoscalers",
 	"job":    "jobs",
+
+	// Singular names of the types with a dedicated getter
+	ResourcePod:            ResourcePods,
+	ResourceDeployment:     ResourceDeployments,
+	ResourceService:        ResourceServices,
+	ResourceIngress:        ResourceIngresses,
+	ResourceConfigMap:      ResourceConfigMaps,
+	ResourceSecret:         ResourceSecrets,
+	ResourceNamespace:      ResourceNamespaces,
+	ResourceNode:           ResourceNodes,
+	ResourceServiceAccount: ResourceServiceAccounts,
+}
+
+// getOptions holds the flags that shape how resources are listed and printed.
+type getOptions struct {
+	outputFormat  string
+	showNamespace bool
+	serverPrint   bool
+	labelSelector string
+	fieldSelector string
+	sortBy        string
+	reverse       bool
+	chunkSize     int64
+	columns       columnOptions
+	decode        bool
+
+	// out receives the output instead of stdout, e.g. one buffer per type in a multi-type get
+	out io.Writer
+	// skipEmpty omits tables without rows instead of printing bare headers
+	skipEmpty bool
+}
+
+// writer returns where output should be written.
+func (o getOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+// render writes a table to the configured writer.
+func (o getOptions) render(table *output.Table) {
+	if o.skipEmpty && table.Len() == 0 {
+		return
+	}
+	table.RenderTo(o.writer())
+}
+
+// wide reports whether the extra -o wide columns should be shown.
+func (o getOptions) wide() bool {
+	return o.outputFormat == OutputFormatWide
+}
+
+// listOptions returns the list options every getter passes to the API server.
+func (o getOptions) listOptions() metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: o.labelSelector,
+		FieldSelector: o.fieldSelector,
+		Limit:         o.chunkSize,
+	}
+}
+
+// sort orders items by --sort-by, leaving them in server order when it is unset.
+func (o getOptions) sort(items interface{}) error {
+	if o.sortBy == "" {
+		return nil
+	}
+	if err := output.SortObjects(items, o.sortBy, o.reverse); err != nil {
+		return errors.WrapError(err, "Failed to sort by "+o.sortBy)
+	}
+	return nil
+}
+
+// printStructured prints items in a structured output format. A get by name
+// prints the object itself rather than a list, like kubectl.
+func printStructured[T any](w io.Writer, items []T, single bool, format string) error {
+	if single && len(items) == 1 {
+		return output.WriteObjects(w, items[0], format)
+	}
+	return output.WriteObjects(w, items, format)
 }
 
 // NewGetCommand creates a new get command for displaying Kubernetes resources.
 func NewGetCommand() *cobra.Command {
-	var outputFormat string
+	var opts getOptions
 	var namespace string
+	var allNamespaces bool
 
 	cmd := &cobra.Command{
-		Use:   "get [resource-type] [resource-name]",
+		Use:   "get [resource-type[,resource-type...]] [resource-name...]",
 		Short: "Display one or many resources",
 		Long: `Display one or many resources with enhanced colored table output.
-Supports all standard Kubernetes resource types and shortcuts.`,
+Supports all standard Kubernetes resource types and shortcuts, as well as
+any other type served by the cluster (including CRDs) by resource name,
+short name or kind.group.
+
+Several types can be listed at once (po,svc,deploy or "all"), and single
+objects can be given in type/name form (deploy/api svc/api).`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runGet(cmd, args, outputFormat, namespace)
+			return runGet(cmd, args, namespace, allNamespaces, opts)
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format: table, json, yaml")
+	cmd.Flags().StringVarP(&opts.outputFormat, "output", "o", "table", "Output format: table, wide, json, yaml, jsonpath=..., go-template=..., custom-columns=... (or the -file variants)")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (overrides config)")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "List resources across all namespaces")
+	cmd.Flags().StringVarP(&opts.labelSelector, "selector", "l", "", "Label selector to filter on (e.g. app=api,tier!=cache)")
+	cmd.Flags().StringVar(&opts.fieldSelector, "field-selector", "", "Field selector to filter on (e.g. status.phase=Running)")
+	cmd.Flags().StringVar(&opts.sortBy, "sort-by", "", "Sort list output by a JSONPath expression (e.g. .metadata.creationTimestamp)")
+	cmd.Flags().BoolVar(&opts.reverse, "reverse", false, "Reverse the order given by --sort-by")
+	cmd.Flags().Int64Var(&opts.chunkSize, "chunk-size", DefaultChunkSize, "Fetch large lists in chunks of this many objects (0 disables chunking)")
+	addColumnFlags(cmd, &opts.columns)
+	cmd.Flags().BoolVar(&opts.decode, "decode", false, "Show secret values decoded under stringData in structured output instead of base64 under data")
+	cmd.Flags().BoolVar(&opts.serverPrint, "server-print", false, "Let the API server compute table columns (kubectl-accurate for every type)")
 
 	return cmd
 }
 
-func runGet(cmd *cobra.Command, args []string, outputFormat, namespace string) error {
-	resourceType := args[0]
-	resourceName := ""
-	if len(args) > 1 {
-		resourceName = args[1]
+func runGet(_ *cobra.Command, args []string, namespace string, allNamespaces bool, opts getOptions) error {
+	requests, err := parseGetArgs(args)
+	if err != nil {
+		return errors.WrapError(err, "Invalid resource arguments")
 	}
 
-	// Expand shortcuts
-	if expanded, ok := resourceShortcuts[resourceType]; ok {
-		resourceType = expanded
+	if opts.sortBy != "" {
+		if _, err := output.ParseRelaxedJSONPath("sort", opts.sortBy); err != nil {
+			return errors.WrapError(err, "Invalid --sort-by expression")
+		}
 	}
 
 	client, err := k8s.GetClient()
@@ -74,13 +173,11 @@ func runGet(cmd *cobra.Command, args []string, outputFormat, namespace string) e
 		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
 	}
 
-	// Determine if namespace was explicitly provided
-	namespaceExplicitlySet := cmd.Flags().Changed("namespace")
-	showNamespaceColumn := !namespaceExplicitlySet
-
-	// Get namespace - if not explicitly set, use all namespaces
-	if !namespaceExplicitlySet {
-		namespace = metav1.NamespaceAll // List from all namespaces
+	// Like kubectl, only --all-namespaces lists across namespaces; otherwise
+	// fall back to the configured namespace
+	opts.showNamespace = allNamespaces
+	if allNamespaces {
+		namespace = metav1.NamespaceAll
 	} else if namespace == "" {
 		namespace = config.GetCurrentNamespace()
 		if namespace == "" {
@@ -88,522 +185,520 @@ func runGet(cmd *cobra.Command, args []string, outputFormat, namespace string) e
 		}
 	}
 
+	if len(requests) == 1 {
+		return getByType(client, requests[0].resourceType, namespace, requests[0].name, opts)
+	}
+
+	// Initialize the shared discovery and dynamic clients before fanning out
+	mapper, err := k8s.GetRESTMapper()
+	if err != nil {
+		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
+	}
+	dynamicClient, err := k8s.GetDynamicClient()
+	if err != nil {
+		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
+	}
+
+	if output.IsStructuredFormat(opts.outputFormat) {
+		return getMultipleStructured(dynamicClient, mapper, requests, namespace, opts)
+	}
+	return getMultiple(client, requests, namespace, opts)
+}
+
+// getByType dispatches to the getter for resourceType.
+func getByType(client kubernetes.Interface, resourceType, namespace, name string, opts getOptions) error {
+	if opts.serverPrint && (opts.outputFormat == OutputFormatTable || opts.wide()) {
+		return getServerPrinted(resourceType, namespace, name, opts)
+	}
+
 	// Handle namespace-scoped vs cluster-scoped resources
 	switch strings.ToLower(resourceType) {
 	case ResourcePods, ResourcePo:
-		return getPods(client, namespace, resourceName, outputFormat, showNamespaceColumn)
+		return getPods(client, namespace, name, opts)
 	case ResourceDeployments, ResourceDeploy:
-		return getDeployments(client, namespace, resourceName, outputFormat, showNamespaceColumn)
+		return getDeployments(client, namespace, name, opts)
 	case ResourceServices, ResourceSvc:
-		return getServices(client, namespace, resourceName, outputFormat, showNamespaceColumn)
+		return getServices(client, namespace, name, opts)
 	case "configmaps", "cm":
-		return getConfigMaps(client, namespace, resourceName, outputFormat, showNamespaceColumn)
+		return getConfigMaps(client, namespace, name, opts)
 	case "secrets", "sec":
-		return getSecrets(client, namespace, resourceName, outputFormat, showNamespaceColumn)
+		return getSecrets(client, namespace, name, opts)
 	case ResourceIngresses, ResourceIng:
-		return getIngresses(client, namespace, resourceName, outputFormat, showNamespaceColumn)
+		return getIngresses(client, namespace, name, opts)
 	case ResourceServiceAccounts, ResourceSa:
-		return getServiceAccounts(client, namespace, resourceName, outputFormat, showNamespaceColumn)
+		return getServiceAccounts(client, namespace, name, opts)
 	case "namespaces", "ns":
-		return getNamespaces(client, resourceName, outputFormat)
+		return getNamespaces(client, name, opts)
 	case "nodes", "no":
-		return getNodes(client, resourceName, outputFormat)
+		return getNodes(client, name, opts)
 	default:
-		return errors.WrapError(
-			fmt.Errorf("resource type %s not yet implemented", resourceType),
-			fmt.Sprintf("Resource type '%s' is not yet supported", resourceType),
-		)
+		// Anything else (StatefulSets, Jobs, CRDs, ...) is resolved through discovery
+		return getResource(resourceType, namespace, name, opts)
 	}
 }
 
-func getPods(client interface{}, namespace, name, outputFormat string, showNamespace bool) error {
+func getPods(client interface{}, namespace, name string, opts getOptions) error {
 	clientset, ok := client.(kubernetes.Interface)
 	if !ok {
 		return fmt.Errorf("invalid client type")
 	}
+	pods := clientset.CoreV1().Pods(namespace)
 
-	var pods []*corev1.Pod
-
-	if name != "" {
-		pod, err := clientset.CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "pod", name, namespace)
-		}
-		pods = []*corev1.Pod{pod}
-	} else {
-		podList, err := clientset.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "pods", "", namespace)
-		}
-		for i := range podList.Items {
-			pods = append(pods, &podList.Items[i])
-		}
-	}
+	return printResources(resourceLister[*corev1.Pod]{
+		singular:   "pod",
+		kind:       "pod",
+		plural:     "pods",
+		namespace:  namespace,
+		namespaced: true,
+		get: func(name string) (*corev1.Pod, error) {
+			return pods.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*corev1.Pod, string, error) {
+			podList, err := pods.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			items := make([]*corev1.Pod, 0, len(podList.Items))
+			for i := range podList.Items {
+				items = append(items, &podList.Items[i])
+			}
+			return items, podList.Continue, nil
+		},
+		headers: podHeaders(opts.wide()),
+		row:     func(pod *corev1.Pod) []string { return podRow(pod, opts.wide()) },
+	}, name, opts)
+}
 
-	if outputFormat == OutputFormatJSON {
-		return output.FormatJSON(pods)
-	}
-	if outputFormat == OutputFormatYAML {
-		return output.FormatYAML(pods)
+// podHeaders and podRow are the columns of get pods, which watch shares.
+func podHeaders(wide bool) []string {
+	headers := []string{"NAME", "READY", "STATUS", "RESTARTS", "AGE"}
+	if wide {
+		headers = append(headers, "IP", "NODE", "NOMINATED NODE", "READINESS GATES")
 	}
+	return headers
+}
 
-	// Table output
-	headers := []string{"NAME", "READY", "STATUS", "RESTARTS", "AGE"}
-	if showNamespace {
-		headers = []string{"NAMESPACE", "NAME", "READY", "STATUS", "RESTARTS", "AGE"}
-	}
-	table := output.NewTable(headers)
-	for _, pod := range pods {
-		ready := fmt.Sprintf("%d/%d", getReadyContainers(pod), len(pod.Spec.Containers))
-		status := getPodStatus(pod)
-		restarts := getRestartCount(pod)
-		age := getAge(pod.CreationTimestamp)
-
-		row := []string{
-			pod.Name,
-			ready,
-			status,
-			fmt.Sprintf("%d", restarts),
-			age,
-		}
-		if showNamespace {
-			row = []string{
-				pod.Namespace,
-				pod.Name,
-				ready,
-				status,
-				fmt.Sprintf("%d", restarts),
-				age,
-			}
-		}
-		table.AddRow(row)
+func podRow(pod *corev1.Pod, wide bool) []string {
+	ready := fmt.Sprintf("%d/%d", getReadyContainers(pod), len(pod.Spec.Containers))
+	status := getPodStatus(pod)
+	restarts := getRestartCount(pod)
+	age := getAge(pod.CreationTimestamp)
+
+	row := []string{
+		pod.Name,
+		ready,
+		status,
+		fmt.Sprintf("%d", restarts),
+		age,
+	}
+	if wide {
+		row = append(row,
+			valueOrNone(pod.Status.PodIP),
+			valueOrNone(pod.Spec.NodeName),
+			valueOrNone(pod.Status.NominatedNodeName),
+			getReadinessGates(pod),
+		)
 	}
-	table.Render()
-	return nil
+	return row
 }
 
-func getDeployments(client interface{}, namespace, name, outputFormat string, showNamespace bool) error {
+func getDeployments(client interface{}, namespace, name string, opts getOptions) error {
 	clientset, ok := client.(kubernetes.Interface)
 	if !ok {
 		return fmt.Errorf("invalid client type")
 	}
+	deployments := clientset.AppsV1().Deployments(namespace)
 
-	var deployments []interface{}
+	return printResources(resourceLister[*appsv1.Deployment]{
+		singular:   "deployment",
+		kind:       "deployment.apps",
+		plural:     "deployments",
+		namespace:  namespace,
+		namespaced: true,
+		get: func(name string) (*appsv1.Deployment, error) {
+			return deployments.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*appsv1.Deployment, string, error) {
+			deploymentList, err := deployments.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			// Optimize: pre-allocate slice capacity
+			items := make([]*appsv1.Deployment, 0, len(deploymentList.Items))
+			for i := range deploymentList.Items {
+				items = append(items, &deploymentList.Items[i])
+			}
+			return items, deploymentList.Continue, nil
+		},
+		headers: deploymentHeaders(opts.wide()),
+		row:     func(d *appsv1.Deployment) []string { return deploymentRow(d, opts.wide()) },
+	}, name, opts)
+}
 
-	if name != "" {
-		deployment, err := clientset.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "deployment", name, namespace)
-		}
-		deployments = []interface{}{deployment}
-	} else {
-		deploymentList, err := clientset.AppsV1().Deployments(namespace).List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "deployments", "", namespace)
-		}
-		// Optimize: pre-allocate slice capacity
-		deployments = make([]interface{}, 0, len(deploymentList.Items))
-		for i := range deploymentList.Items {
-			deployments = append(deployments, &deploymentList.Items[i])
-		}
+// deploymentHeaders and deploymentRow are the columns of get deployments, which watch shares.
+func deploymentHeaders(wide bool) []string {
+	headers := []string{"NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE"}
+	if wide {
+		headers = append(headers, "CONTAINERS", "IMAGES", "SELECTOR")
 	}
+	return headers
+}
 
-	if outputFormat == OutputFormatJSON {
-		return output.FormatJSON(deployments)
-	}
-	if outputFormat == OutputFormatYAML {
-		return output.FormatYAML(deployments)
-	}
+func deploymentRow(d *appsv1.Deployment, wide bool) []string {
+	ready := fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, getDesiredReplicas(d.Spec.Replicas))
+	upToDate := fmt.Sprintf("%d", d.Status.UpdatedReplicas)
+	available := fmt.Sprintf("%d", d.Status.AvailableReplicas)
+	age := getAge(d.CreationTimestamp)
 
-	// Table output
-	headers := []string{"NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE"}
-	if showNamespace {
-		headers = []string{"NAMESPACE", "NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE"}
-	}
-	table := output.NewTable(headers)
-	for _, dep := range deployments {
-		d, ok := dep.(*appsv1.Deployment)
-		if !ok {
-			continue
-		}
-		ready := fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, *d.Spec.Replicas)
-		upToDate := fmt.Sprintf("%d", d.Status.UpdatedReplicas)
-		available := fmt.Sprintf("%d", d.Status.AvailableReplicas)
-		age := getAge(d.CreationTimestamp)
-
-		row := []string{
-			d.Name,
-			ready,
-			upToDate,
-			available,
-			age,
-		}
-		if showNamespace {
-			row = []string{
-				d.Namespace,
-				d.Name,
-				ready,
-				upToDate,
-				available,
-				age,
-			}
-		}
-		table.AddRow(row)
+	row := []string{
+		d.Name,
+		ready,
+		upToDate,
+		available,
+		age,
 	}
-	table.Render()
-	return nil
+	if wide {
+		containers, images := getContainersAndImages(d.Spec.Template.Spec.Containers)
+		row = append(row, containers, images, metav1.FormatLabelSelector(d.Spec.Selector))
+	}
+	return row
 }
 
-func getServices(client interface{}, namespace, name, outputFormat string, showNamespace bool) error {
+func getServices(client interface{}, namespace, name string, opts getOptions) error {
 	clientset, ok := client.(kubernetes.Interface)
 	if !ok {
 		return fmt.Errorf("invalid client type")
 	}
+	services := clientset.CoreV1().Services(namespace)
 
-	var services []interface{}
+	return printResources(resourceLister[*corev1.Service]{
+		singular:   "service",
+		kind:       "service",
+		plural:     "services",
+		namespace:  namespace,
+		namespaced: true,
+		get: func(name string) (*corev1.Service, error) {
+			return services.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*corev1.Service, string, error) {
+			serviceList, err := services.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			items := make([]*corev1.Service, 0, len(serviceList.Items))
+			for i := range serviceList.Items {
+				items = append(items, &serviceList.Items[i])
+			}
+			return items, serviceList.Continue, nil
+		},
+		headers: serviceHeaders(opts.wide()),
+		row:     func(s *corev1.Service) []string { return serviceRow(s, opts.wide()) },
+	}, name, opts)
+}
 
-	if name != "" {
-		service, err := clientset.CoreV1().Services(namespace).Get(context.Background(), name, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "service", name, namespace)
-		}
-		services = []interface{}{service}
-	} else {
-		serviceList, err := clientset.CoreV1().Services(namespace).List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "services", "", namespace)
-		}
-		for i := range serviceList.Items {
-			services = append(services, &serviceList.Items[i])
-		}
+// serviceHeaders and serviceRow are the columns of get services, which watch shares.
+func serviceHeaders(wide bool) []string {
+	headers := []string{"NAME", "TYPE", "CLUSTER-IP", "EXTERNAL-IP", "PORT(S)", "AGE"}
+	if wide {
+		headers = append(headers, "SELECTOR")
 	}
+	return headers
+}
 
-	if outputFormat == OutputFormatJSON {
-		return output.FormatJSON(services)
+func serviceRow(s *corev1.Service, wide bool) []string {
+	// Optimize string building for ports
+	var portsBuilder strings.Builder
+	for i, port := range s.Spec.Ports {
+		if i > 0 {
+			portsBuilder.WriteString(",")
+		}
+		portsBuilder.WriteString(fmt.Sprintf("%d/%s", port.Port, port.Protocol))
 	}
-	if outputFormat == OutputFormatYAML {
-		return output.FormatYAML(services)
+	ports := portsBuilder.String()
+	if ports == "" {
+		ports = NoneValue
 	}
 
-	// Table output
-	headers := []string{"NAME", "TYPE", "CLUSTER-IP", "EXTERNAL-IP", "PORT(S)", "AGE"}
-	if showNamespace {
-		headers = []string{"NAMESPACE", "NAME", "TYPE", "CLUSTER-IP", "EXTERNAL-IP", "PORT(S)", "AGE"}
-	}
-	table := output.NewTable(headers)
-	for _, svc := range services {
-		s, ok := svc.(*corev1.Service)
-		if !ok {
-			continue
-		}
-		// Optimize string building for ports
-		var portsBuilder strings.Builder
-		for i, port := range s.Spec.Ports {
-			if i > 0 {
-				portsBuilder.WriteString(",")
-			}
-			portsBuilder.WriteString(fmt.Sprintf("%d/%s", port.Port, port.Protocol))
-		}
-		ports := portsBuilder.String()
-		if ports == "" {
-			ports = NoneValue
-		}
-
-		externalIP := NoneValue
-		if len(s.Status.LoadBalancer.Ingress) > 0 {
-			externalIP = s.Status.LoadBalancer.Ingress[0].IP
-		}
+	externalIP := NoneValue
+	if len(s.Status.LoadBalancer.Ingress) > 0 {
+		externalIP = s.Status.LoadBalancer.Ingress[0].IP
+	}
 
-		clusterIP := s.Spec.ClusterIP
-		if clusterIP == "" {
-			clusterIP = NoneValue
-		}
+	clusterIP := s.Spec.ClusterIP
+	if clusterIP == "" {
+		clusterIP = NoneValue
+	}
 
-		age := getAge(s.CreationTimestamp)
+	age := getAge(s.CreationTimestamp)
 
-		row := []string{
-			s.Name,
-			string(s.Spec.Type),
-			clusterIP,
-			externalIP,
-			ports,
-			age,
-		}
-		if showNamespace {
-			row = []string{
-				s.Namespace,
-				s.Name,
-				string(s.Spec.Type),
-				clusterIP,
-				externalIP,
-				ports,
-				age,
-			}
-		}
-		table.AddRow(row)
+	row := []string{
+		s.Name,
+		string(s.Spec.Type),
+		clusterIP,
+		externalIP,
+		ports,
+		age,
 	}
-	table.Render()
-	return nil
+	if wide {
+		row = append(row, labels.FormatLabels(s.Spec.Selector))
+	}
+	return row
 }
 
-func getNamespaces(client interface{}, name, outputFormat string) error {
+func getNamespaces(client interface{}, name string, opts getOptions) error {
 	clientset, ok := client.(kubernetes.Interface)
 	if !ok {
 		return fmt.Errorf("invalid client type")
 	}
+	namespaces := clientset.CoreV1().Namespaces()
 
-	var namespaces []interface{}
+	return printResources(resourceLister[*corev1.Namespace]{
+		singular: "namespace",
+		kind:     "namespace",
+		plural:   "namespaces",
+		get: func(name string) (*corev1.Namespace, error) {
+			return namespaces.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*corev1.Namespace, string, error) {
+			namespaceList, err := namespaces.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			items := make([]*corev1.Namespace, 0, len(namespaceList.Items))
+			for i := range namespaceList.Items {
+				items = append(items, &namespaceList.Items[i])
+			}
+			return items, namespaceList.Continue, nil
+		},
+		headers: namespaceHeaders(opts.wide()),
+		row:     func(n *corev1.Namespace) []string { return namespaceRow(n, opts.wide()) },
+	}, name, opts)
+}
 
-	if name != "" {
-		namespace, err := clientset.CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "namespace", name, "")
-		}
-		namespaces = []interface{}{namespace}
-	} else {
-		namespaceList, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "namespaces", "", "")
-		}
-		for i := range namespaceList.Items {
-			namespaces = append(namespaces, &namespaceList.Items[i])
-		}
-	}
+// namespaceHeaders takes wide like the other column sets, though namespaces
+// have no wide columns.
+func namespaceHeaders(_ bool) []string {
+	return []string{"NAME", "STATUS", "AGE"}
+}
 
-	if outputFormat == OutputFormatJSON {
-		return output.FormatJSON(namespaces)
-	}
-	if outputFormat == OutputFormatYAML {
-		return output.FormatYAML(namespaces)
+func namespaceRow(n *corev1.Namespace, _ bool) []string {
+	status := "Active"
+	if n.Status.Phase != "" {
+		status = string(n.Status.Phase)
 	}
+	age := getAge(n.CreationTimestamp)
 
-	// Table output
-	table := output.NewTable([]string{"NAME", "STATUS", "AGE"})
-	for _, ns := range namespaces {
-		n, ok := ns.(*corev1.Namespace)
-		if !ok {
-			continue
-		}
-		status := "Active"
-		if n.Status.Phase != "" {
-			status = string(n.Status.Phase)
-		}
-		age := getAge(n.CreationTimestamp)
-
-		table.AddRow([]string{
-			n.Name,
-			status,
-			age,
-		})
+	return []string{
+		n.Name,
+		status,
+		age,
 	}
-	table.Render()
-	return nil
 }
 
-func getNodes(client interface{}, name, outputFormat string) error {
+func getNodes(client interface{}, name string, opts getOptions) error {
 	clientset, ok := client.(kubernetes.Interface)
 	if !ok {
 		return fmt.Errorf("invalid client type")
 	}
+	nodes := clientset.CoreV1().Nodes()
 
-	var nodes []interface{}
-
-	if name != "" {
-		node, err := clientset.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "node", name, "")
-		}
-		nodes = []interface{}{node}
-	} else {
-		nodeList, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "nodes", "", "")
-		}
-		for i := range nodeList.Items {
-			nodes = append(nodes, &nodeList.Items[i])
-		}
-	}
+	return printResources(resourceLister[*corev1.Node]{
+		singular: "node",
+		kind:     "node",
+		plural:   "nodes",
+		get: func(name string) (*corev1.Node, error) {
+			return nodes.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*corev1.Node, string, error) {
+			nodeList, err := nodes.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			items := make([]*corev1.Node, 0, len(nodeList.Items))
+			for i := range nodeList.Items {
+				items = append(items, &nodeList.Items[i])
+			}
+			return items, nodeList.Continue, nil
+		},
+		headers: nodeHeaders(opts.wide()),
+		row:     func(n *corev1.Node) []string { return nodeRow(n, opts.wide()) },
+	}, name, opts)
+}
 
-	if outputFormat == OutputFormatJSON {
-		return output.FormatJSON(nodes)
+// nodeHeaders and nodeRow are the columns of get nodes, which watch shares.
+func nodeHeaders(wide bool) []string {
+	headers := []string{"NAME", "STATUS", "ROLES", "AGE", "VERSION"}
+	if wide {
+		headers = append(headers, "INTERNAL-IP", "EXTERNAL-IP", "OS-IMAGE", "KERNEL-VERSION", "CONTAINER-RUNTIME")
 	}
-	if outputFormat == OutputFormatYAML {
-		return output.FormatYAML(nodes)
-	}
-
-	// Table output
-	table := output.NewTable([]string{"NAME", "STATUS", "ROLES", "AGE", "VERSION"})
-	for _, node := range nodes {
-		n, ok := node.(*corev1.Node)
-		if !ok {
-			continue
-		}
-		status := StatusReady
-		for _, condition := range n.Status.Conditions {
-			if condition.Type == corev1.NodeReady {
-				if condition.Status != corev1.ConditionTrue {
-					status = StatusNotReady
-				}
-				break
-			}
-		}
+	return headers
+}
 
-		roles := []string{}
-		for label := range n.Labels {
-			if strings.HasPrefix(label, "node-role.kubernetes.io/") {
-				roles = append(roles, strings.TrimPrefix(label, "node-role.kubernetes.io/"))
+func nodeRow(n *corev1.Node, wide bool) []string {
+	status := StatusReady
+	for _, condition := range n.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			if condition.Status != corev1.ConditionTrue {
+				status = StatusNotReady
 			}
+			break
 		}
-		roleStr := strings.Join(roles, ",")
-		if roleStr == "" {
-			roleStr = NoneValue
-		}
+	}
 
-		age := getAge(n.CreationTimestamp)
-		version := n.Status.NodeInfo.KubeletVersion
+	roleStr := valueOrNone(strings.Join(getNodeRoles(n), ","))
+	age := getAge(n.CreationTimestamp)
+	version := n.Status.NodeInfo.KubeletVersion
 
-		table.AddRow([]string{
-			n.Name,
-			status,
-			roleStr,
-			age,
-			version,
-		})
+	row := []string{
+		n.Name,
+		status,
+		roleStr,
+		age,
+		version,
 	}
-	table.Render()
-	return nil
+	if wide {
+		row = append(row,
+			getNodeAddress(n, corev1.NodeInternalIP),
+			getNodeAddress(n, corev1.NodeExternalIP),
+			n.Status.NodeInfo.OSImage,
+			n.Status.NodeInfo.KernelVersion,
+			n.Status.NodeInfo.ContainerRuntimeVersion,
+		)
+	}
+	return row
 }
 
-func getConfigMaps(client interface{}, namespace, name, outputFormat string, showNamespace bool) error {
+func getConfigMaps(client interface{}, namespace, name string, opts getOptions) error {
 	clientset, ok := client.(kubernetes.Interface)
 	if !ok {
 		return fmt.Errorf("invalid client type")
 	}
+	configMaps := clientset.CoreV1().ConfigMaps(namespace)
 
-	var configMaps []interface{}
-
-	if name != "" {
-		cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(context.Background(), name, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "configmap", name, namespace)
-		}
-		configMaps = []interface{}{cm}
-	} else {
-		cmList, err := clientset.CoreV1().ConfigMaps(namespace).List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "configmaps", "", namespace)
-		}
-		for i := range cmList.Items {
-			configMaps = append(configMaps, &cmList.Items[i])
-		}
-	}
+	return printResources(resourceLister[*corev1.ConfigMap]{
+		singular:   "configmap",
+		kind:       "configmap",
+		plural:     "configmaps",
+		namespace:  namespace,
+		namespaced: true,
+		get: func(name string) (*corev1.ConfigMap, error) {
+			return configMaps.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*corev1.ConfigMap, string, error) {
+			cmList, err := configMaps.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			items := make([]*corev1.ConfigMap, 0, len(cmList.Items))
+			for i := range cmList.Items {
+				items = append(items, &cmList.Items[i])
+			}
+			return items, cmList.Continue, nil
+		},
+		headers: configMapHeaders(opts.wide()),
+		row:     func(c *corev1.ConfigMap) []string { return configMapRow(c, opts.wide()) },
+	}, name, opts)
+}
 
-	if outputFormat == OutputFormatJSON {
-		return output.FormatJSON(configMaps)
-	}
-	if outputFormat == OutputFormatYAML {
-		return output.FormatYAML(configMaps)
+// configMapHeaders and configMapRow are the columns of get configmaps, which watch shares.
+func configMapHeaders(wide bool) []string {
+	headers := []string{"NAME", "DATA", "AGE"}
+	if wide {
+		headers = append(headers, "KEYS")
 	}
+	return headers
+}
 
-	// Table output
-	headers := []string{"NAME", "DATA", "AGE"}
-	if showNamespace {
-		headers = []string{"NAMESPACE", "NAME", "DATA", "AGE"}
-	}
-	table := output.NewTable(headers)
-	for _, cm := range configMaps {
-		c, ok := cm.(*corev1.ConfigMap)
-		if !ok {
-			continue
-		}
-		dataCount := len(c.Data)
-		age := getAge(c.CreationTimestamp)
+func configMapRow(c *corev1.ConfigMap, wide bool) []string {
+	dataCount := len(c.Data)
+	age := getAge(c.CreationTimestamp)
 
-		row := []string{
-			c.Name,
-			fmt.Sprintf("%d", dataCount),
-			age,
+	row := []string{
+		c.Name,
+		fmt.Sprintf("%d", dataCount),
+		age,
+	}
+	if wide {
+		keys := make([]string, 0, len(c.Data)+len(c.BinaryData))
+		for key := range c.Data {
+			keys = append(keys, key)
 		}
-		if showNamespace {
-			row = []string{
-				c.Namespace,
-				c.Name,
-				fmt.Sprintf("%d", dataCount),
-				age,
-			}
+		for key := range c.BinaryData {
+			keys = append(keys, key)
 		}
-		table.AddRow(row)
+		row = append(row, joinSortedOrNone(keys))
 	}
-	table.Render()
-	return nil
+	return row
 }
 
-func getSecrets(client interface{}, namespace, name, outputFormat string, showNamespace bool) error {
+func getSecrets(client interface{}, namespace, name string, opts getOptions) error {
 	clientset, ok := client.(kubernetes.Interface)
 	if !ok {
 		return fmt.Errorf("invalid client type")
 	}
+	secrets := clientset.CoreV1().Secrets(namespace)
 
-	var secrets []interface{}
+	return printResources(resourceLister[*corev1.Secret]{
+		singular:   "secret",
+		kind:       "secret",
+		plural:     "secrets",
+		namespace:  namespace,
+		namespaced: true,
+		get: func(name string) (*corev1.Secret, error) {
+			return secrets.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*corev1.Secret, string, error) {
+			secretList, err := secrets.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			items := make([]*corev1.Secret, 0, len(secretList.Items))
+			for i := range secretList.Items {
+				items = append(items, &secretList.Items[i])
+			}
+			return items, secretList.Continue, nil
+		},
+		forOutput: func(s *corev1.Secret) (interface{}, error) {
+			if !opts.decode {
+				return s, nil
+			}
+			return redactSecret(s, true)
+		},
+		headers: secretHeaders(opts.wide()),
+		row:     func(s *corev1.Secret) []string { return secretRow(s, opts.wide()) },
+	}, name, opts)
+}
 
-	if name != "" {
-		secret, err := clientset.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "secret", name, namespace)
-		}
-		secrets = []interface{}{secret}
-	} else {
-		secretList, err := clientset.CoreV1().Secrets(namespace).List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "secrets", "", namespace)
-		}
-		for i := range secretList.Items {
-			secrets = append(secrets, &secretList.Items[i])
-		}
+// secretHeaders and secretRow are the columns of get secrets, which watch shares.
+func secretHeaders(wide bool) []string {
+	headers := []string{"NAME", "TYPE", "DATA", "AGE"}
+	if wide {
+		headers = append(headers, "KEYS")
 	}
+	return headers
+}
 
-	if outputFormat == OutputFormatJSON {
-		return output.FormatJSON(secrets)
-	}
-	if outputFormat == OutputFormatYAML {
-		return output.FormatYAML(secrets)
+func secretRow(s *corev1.Secret, wide bool) []string {
+	secretType := string(s.Type)
+	if secretType == "" {
+		secretType = SecretTypeOpaque
 	}
+	dataCount := len(s.Data)
+	age := getAge(s.CreationTimestamp)
 
-	// Table output
-	headers := []string{"NAME", "TYPE", "DATA", "AGE"}
-	if showNamespace {
-		headers = []string{"NAMESPACE", "NAME", "TYPE", "DATA", "AGE"}
-	}
-	table := output.NewTable(headers)
-	for _, secret := range secrets {
-		s, ok := secret.(*corev1.Secret)
-		if !ok {
-			continue
-		}
-		secretType := string(s.Type)
-		if secretType == "" {
-			secretType = SecretTypeOpaque
-		}
-		dataCount := len(s.Data)
-		age := getAge(s.CreationTimestamp)
-
-		row := []string{
-			s.Name,
-			secretType,
-			fmt.Sprintf("%d", dataCount),
-			age,
-		}
-		if showNamespace {
-			row = []string{
-				s.Namespace,
-				s.Name,
-				secretType,
-				fmt.Sprintf("%d", dataCount),
-				age,
-			}
+	row := []string{
+		s.Name,
+		secretType,
+		fmt.Sprintf("%d", dataCount),
+		age,
+	}
+	if wide {
+		// Only key names are shown, never values
+		keys := make([]string, 0, len(s.Data))
+		for key := range s.Data {
+			keys = append(keys, key)
 		}
-		table.AddRow(row)
+		row = append(row, joinSortedOrNone(keys))
 	}
-	table.Render()
-	return nil
+	return row
 }
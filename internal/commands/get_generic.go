@@ -0,0 +1,316 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/k8s"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// crdResource is the resource used to look up printer columns of custom resources.
+var crdResource = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// resolvedResource describes a resource type resolved through API discovery.
+type resolvedResource struct {
+	gvr        schema.GroupVersionResource
+	kind       string
+	namespaced bool
+}
+
+// printerColumn is a table column backed by a JSONPath expression.
+type printerColumn struct {
+	name     string
+	jsonPath string
+	colType  string
+	priority int64
+	parser   *jsonpath.JSONPath
+}
+
+// defaultPrinterColumns are used when a resource does not declare its own columns.
+func defaultPrinterColumns() []printerColumn {
+	return []printerColumn{{name: "Age", jsonPath: ".metadata.creationTimestamp", colType: "date"}}
+}
+
+// resolveResource resolves a resource name, short name or kind.group
+// (e.g. "sts", "certificates.cert-manager.io") through the REST mapper.
+func resolveResource(mapper meta.RESTMapper, arg string) (*resolvedResource, error) {
+	arg = strings.ToLower(arg)
+
+	var gvr schema.GroupVersionResource
+	var err error
+
+	fullySpecified, groupResource := schema.ParseResourceArg(arg)
+	if fullySpecified != nil {
+		gvr, err = mapper.ResourceFor(*fullySpecified)
+	}
+	if gvr.Empty() {
+		gvr, err = mapper.ResourceFor(groupResource.WithVersion(""))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gvk, err := mapper.KindFor(gvr)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resolvedResource{
+		gvr:        mapping.Resource,
+		kind:       gvk.Kind,
+		namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}, nil
+}
+
+// lookupResource is resolveResource with a user-facing error for unknown types.
+func lookupResource(mapper meta.RESTMapper, resourceType string) (*resolvedResource, error) {
+	res, err := resolveResource(mapper, resourceType)
+	if err != nil {
+		return nil, errors.WrapError(
+			fmt.Errorf("resource type %s not found: %w", resourceType, err),
+			fmt.Sprintf("Resource type '%s' is not served by the cluster", resourceType),
+		)
+	}
+	return res, nil
+}
+
+// getResource handles resource types without a dedicated getter by resolving
+// them through discovery and listing them with the dynamic client. Tables of
+// built-in types are rendered by the API server.
+func getResource(resourceType, namespace, name string, opts getOptions) error {
+	mapper, err := k8s.GetRESTMapper()
+	if err != nil {
+		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
+	}
+
+	res, err := lookupResource(mapper, resourceType)
+	if err != nil {
+		return err
+	}
+
+	// Built-in types declare no printer columns; the API server renders them
+	// with the columns kubectl shows
+	if !output.IsStructuredFormat(opts.outputFormat) && isBuiltinResource(res) {
+		clientset, err := k8s.GetClient()
+		if err != nil {
+			return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
+		}
+		return getServerTable(clientset.Discovery().RESTClient(), res, namespace, name, opts)
+	}
+
+	client, err := k8s.GetDynamicClient()
+	if err != nil {
+		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
+	}
+
+	return getGeneric(client, res, namespace, name, opts)
+}
+
+// isBuiltinResource reports whether res is one of the types Kubernetes itself
+// serves, as opposed to a custom resource or an aggregated API.
+func isBuiltinResource(res *resolvedResource) bool {
+	return scheme.Scheme.Recognizes(res.gvr.GroupVersion().WithKind(res.kind))
+}
+
+func getGeneric(client dynamic.Interface, res *resolvedResource, namespace, name string, opts getOptions) error {
+	lister := unstructuredLister(client, res, namespace)
+	if opts.decode {
+		lister.forOutput = func(item *unstructured.Unstructured) (interface{}, error) {
+			decoded := item.DeepCopy()
+			return decoded, redactSecretObject(decoded.Object, true)
+		}
+	}
+
+	// Printer columns cost an extra request, so only look them up for tables
+	if !output.IsStructuredFormat(opts.outputFormat) {
+		columns := getPrinterColumns(client, res, opts.wide())
+		lister.headers = []string{"NAME"}
+		for _, col := range columns {
+			lister.headers = append(lister.headers, strings.ToUpper(col.name))
+		}
+		lister.row = func(item *unstructured.Unstructured) []string {
+			row := []string{item.GetName()}
+			for _, col := range columns {
+				row = append(row, col.value(item))
+			}
+			return row
+		}
+	}
+
+	return printResources(lister, name, opts)
+}
+
+// unstructuredLister fetches any resource type through the dynamic client.
+func unstructuredLister(client dynamic.Interface, res *resolvedResource, namespace string) resourceLister[*unstructured.Unstructured] {
+	var resourceClient dynamic.ResourceInterface = client.Resource(res.gvr)
+	if res.namespaced {
+		resourceClient = client.Resource(res.gvr).Namespace(namespace)
+	} else {
+		namespace = ""
+	}
+
+	return resourceLister[*unstructured.Unstructured]{
+		singular:   strings.ToLower(res.kind),
+		plural:     res.gvr.Resource,
+		kind:       displayKind(res.kind, res.gvr.Group),
+		namespace:  namespace,
+		namespaced: res.namespaced,
+		get: func(name string) (*unstructured.Unstructured, error) {
+			return resourceClient.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*unstructured.Unstructured, string, error) {
+			list, err := resourceClient.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			items := make([]*unstructured.Unstructured, 0, len(list.Items))
+			for i := range list.Items {
+				items = append(items, &list.Items[i])
+			}
+			return items, list.GetContinue(), nil
+		},
+	}
+}
+
+// fetchUnstructured gets a single object by name, or lists the resource.
+func fetchUnstructured(client dynamic.Interface, res *resolvedResource, namespace, name string, opts getOptions) ([]*unstructured.Unstructured, error) {
+	lister := unstructuredLister(client, res, namespace)
+	if name == "" {
+		return listAll(lister, opts)
+	}
+
+	obj, err := lister.get(name)
+	if err != nil {
+		return nil, errors.HandleKubernetesError(err, lister.singular, name, lister.namespace)
+	}
+	return []*unstructured.Unstructured{obj}, nil
+}
+
+// getPrinterColumns returns the additionalPrinterColumns of a custom resource,
+// falling back to the default columns for built-in types or when the CRD
+// cannot be read. Columns with a priority above zero are only included in wide output.
+func getPrinterColumns(client dynamic.Interface, res *resolvedResource, wide bool) []printerColumn {
+	// Built-in types, including dotted groups such as networking.k8s.io, have no CRD
+	if isBuiltinResource(res) {
+		return compileColumns(defaultPrinterColumns())
+	}
+
+	crdName := res.gvr.Resource + "." + res.gvr.Group
+	crd, err := client.Resource(crdResource).Get(context.Background(), crdName, metav1.GetOptions{})
+	if err != nil {
+		return compileColumns(defaultPrinterColumns())
+	}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok || version["name"] != res.gvr.Version {
+			continue
+		}
+
+		rawColumns, _, _ := unstructured.NestedSlice(version, "additionalPrinterColumns")
+		columns := make([]printerColumn, 0, len(rawColumns))
+		for _, raw := range rawColumns {
+			c, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			col := printerColumn{}
+			col.name, _, _ = unstructured.NestedString(c, "name")
+			col.jsonPath, _, _ = unstructured.NestedString(c, "jsonPath")
+			col.colType, _, _ = unstructured.NestedString(c, "type")
+			col.priority, _, _ = unstructured.NestedInt64(c, "priority")
+			if col.priority > 0 && !wide {
+				continue
+			}
+			columns = append(columns, col)
+		}
+		if len(columns) == 0 {
+			columns = defaultPrinterColumns()
+		}
+		return compileColumns(columns)
+	}
+
+	return compileColumns(defaultPrinterColumns())
+}
+
+// compileColumns parses the JSONPath expression of every column once up front.
+func compileColumns(columns []printerColumn) []printerColumn {
+	for i := range columns {
+		parser := jsonpath.New(columns[i].name).AllowMissingKeys(true)
+		if err := parser.Parse(fmt.Sprintf("{%s}", columns[i].jsonPath)); err != nil {
+			continue
+		}
+		columns[i].parser = parser
+	}
+	return columns
+}
+
+// value evaluates the column against obj and formats the result for display.
+func (c *printerColumn) value(obj *unstructured.Unstructured) string {
+	if c.parser == nil {
+		return "<invalid>"
+	}
+
+	results, err := c.parser.FindResults(obj.Object)
+	if err != nil {
+		return NoneValue
+	}
+
+	values := []string{}
+	for _, result := range results {
+		for _, v := range result {
+			if !v.IsValid() || !v.CanInterface() {
+				continue
+			}
+			values = append(values, formatColumnValue(v.Interface(), c.colType))
+		}
+	}
+	if len(values) == 0 {
+		return NoneValue
+	}
+	return strings.Join(values, ",")
+}
+
+func formatColumnValue(value interface{}, colType string) string {
+	switch v := value.(type) {
+	case nil:
+		return NoneValue
+	case string:
+		if colType == "date" {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return getAge(metav1.NewTime(t))
+			}
+		}
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
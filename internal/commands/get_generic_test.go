@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var certificateResource = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+func newTestRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Node"}, meta.RESTScopeRoot)
+	return mapper
+}
+
+func TestResolveResource(t *testing.T) {
+	mapper := newTestRESTMapper()
+
+	tests := []struct {
+		name           string
+		arg            string
+		wantResource   string
+		wantKind       string
+		wantNamespaced bool
+	}{
+		{"plural", "statefulsets", "statefulsets", "StatefulSet", true},
+		{"singular", "statefulset", "statefulsets", "StatefulSet", true},
+		{"resource.group", "certificates.cert-manager.io", "certificates", "Certificate", true},
+		{"kind.group", "Certificate.cert-manager.io", "certificates", "Certificate", true},
+		{"cluster scoped", "nodes", "nodes", "Node", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := resolveResource(mapper, tt.arg)
+			if err != nil {
+				t.Fatalf("resolveResource(%q) failed: %v", tt.arg, err)
+			}
+			if res.gvr.Resource != tt.wantResource {
+				t.Errorf("resource = %q, expected %q", res.gvr.Resource, tt.wantResource)
+			}
+			if res.kind != tt.wantKind {
+				t.Errorf("kind = %q, expected %q", res.kind, tt.wantKind)
+			}
+			if res.namespaced != tt.wantNamespaced {
+				t.Errorf("namespaced = %v, expected %v", res.namespaced, tt.wantNamespaced)
+			}
+		})
+	}
+
+	if _, err := resolveResource(mapper, "doesnotexist"); err == nil {
+		t.Error("resolveResource should fail for unknown resource types")
+	}
+}
+
+func newCertificateClient() *dynamicfake.FakeDynamicClient {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "certificates.cert-manager.io"},
+		"spec": map[string]interface{}{
+			"versions": []interface{}{
+				map[string]interface{}{
+					"name": "v1",
+					"additionalPrinterColumns": []interface{}{
+						map[string]interface{}{
+							"name":     "Ready",
+							"type":     "string",
+							"jsonPath": `.status.conditions[?(@.type=="Ready")].status`,
+						},
+						map[string]interface{}{
+							"name":     "Secret",
+							"type":     "string",
+							"jsonPath": ".spec.secretName",
+						},
+						map[string]interface{}{
+							"name":     "Issuer",
+							"type":     "string",
+							"jsonPath": ".spec.issuerRef.name",
+							"priority": int64(1),
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	cert := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]interface{}{"name": "api-tls", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"secretName": "api-tls",
+			"issuerRef":  map[string]interface{}{"name": "letsencrypt"},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}}
+
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			certificateResource: "CertificateList",
+			crdResource:         "CustomResourceDefinitionList",
+		},
+		crd, cert,
+	)
+}
+
+func TestGetPrinterColumns(t *testing.T) {
+	client := newCertificateClient()
+	res := &resolvedResource{gvr: certificateResource, kind: "Certificate", namespaced: true}
+
+	columns := getPrinterColumns(client, res, false)
+	if len(columns) != 2 {
+		t.Fatalf("Expected 2 columns (priority columns excluded), got %d", len(columns))
+	}
+
+	obj, err := client.Resource(certificateResource).Namespace("default").Get(context.Background(), "api-tls", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get test certificate: %v", err)
+	}
+	if got := columns[0].value(obj); got != "True" {
+		t.Errorf("READY column = %q, expected %q", got, "True")
+	}
+	if got := columns[1].value(obj); got != "api-tls" {
+		t.Errorf("SECRET column = %q, expected %q", got, "api-tls")
+	}
+
+	wideColumns := getPrinterColumns(client, res, true)
+	if len(wideColumns) != 3 {
+		t.Fatalf("Expected 3 columns in wide output, got %d", len(wideColumns))
+	}
+	if got := wideColumns[2].value(obj); got != "letsencrypt" {
+		t.Errorf("ISSUER column = %q, expected %q", got, "letsencrypt")
+	}
+}
+
+func TestGetPrinterColumnsDefault(t *testing.T) {
+	client := newCertificateClient()
+	res := &resolvedResource{
+		gvr:        schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		kind:       "StatefulSet",
+		namespaced: true,
+	}
+
+	columns := getPrinterColumns(client, res, false)
+	if len(columns) != 1 || columns[0].name != "Age" {
+		t.Errorf("Expected default AGE column, got %+v", columns)
+	}
+}
+
+func TestGetPrinterColumnsBuiltinGroup(t *testing.T) {
+	client := newCertificateClient()
+	res := &resolvedResource{
+		gvr:        schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+		kind:       "Ingress",
+		namespaced: true,
+	}
+
+	if !isBuiltinResource(res) {
+		t.Fatal("Expected networking.k8s.io Ingress to be a built-in resource")
+	}
+	if isBuiltinResource(&resolvedResource{gvr: certificateResource, kind: "Certificate"}) {
+		t.Error("Expected Certificate to be a custom resource")
+	}
+
+	columns := getPrinterColumns(client, res, false)
+	if len(columns) != 1 || columns[0].name != "Age" {
+		t.Errorf("Expected default AGE column, got %+v", columns)
+	}
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("Expected no CRD lookup for a built-in type, got %v", actions)
+	}
+}
+
+func TestGetGeneric(t *testing.T) {
+	client := newCertificateClient()
+	res := &resolvedResource{gvr: certificateResource, kind: "Certificate", namespaced: true}
+
+	if err := getGeneric(client, res, "default", "", getOptions{outputFormat: "table"}); err != nil {
+		t.Errorf("getGeneric failed: %v", err)
+	}
+	if err := getGeneric(client, res, "default", "api-tls", getOptions{outputFormat: "json"}); err != nil {
+		t.Errorf("getGeneric with name failed: %v", err)
+	}
+	if err := getGeneric(client, res, "default", "missing", getOptions{outputFormat: "table"}); err == nil {
+		t.Error("getGeneric should fail for a missing resource")
+	}
+}
@@ -3,130 +3,143 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"github.com/robertusnegoro/k8ctl/internal/errors"
-	"github.com/robertusnegoro/k8ctl/internal/output"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-func getIngresses(client kubernetes.Interface, namespace, name, outputFormat string, showNamespace bool) error {
-	var ingresses []interface{}
+func getIngresses(client kubernetes.Interface, namespace, name string, opts getOptions) error {
+	ingresses := client.NetworkingV1().Ingresses(namespace)
 
-	if name != "" {
-		ing, err := client.NetworkingV1().Ingresses(namespace).Get(context.Background(), name, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "ingress", name, namespace)
-		}
-		ingresses = []interface{}{ing}
-	} else {
-		ingList, err := client.NetworkingV1().Ingresses(namespace).List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "ingresses", "", namespace)
-		}
-		for i := range ingList.Items {
-			ingresses = append(ingresses, &ingList.Items[i])
-		}
-	}
-
-	if outputFormat == OutputFormatJSON {
-		return output.FormatJSON(ingresses)
-	}
-	if outputFormat == OutputFormatYAML {
-		return output.FormatYAML(ingresses)
-	}
+	return printResources(resourceLister[*networkingv1.Ingress]{
+		singular:   "ingress",
+		kind:       "ingress.networking.k8s.io",
+		plural:     "ingresses",
+		namespace:  namespace,
+		namespaced: true,
+		get: func(name string) (*networkingv1.Ingress, error) {
+			return ingresses.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*networkingv1.Ingress, string, error) {
+			ingList, err := ingresses.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			items := make([]*networkingv1.Ingress, 0, len(ingList.Items))
+			for i := range ingList.Items {
+				items = append(items, &ingList.Items[i])
+			}
+			return items, ingList.Continue, nil
+		},
+		headers: ingressHeaders(opts.wide()),
+		row:     func(i *networkingv1.Ingress) []string { return ingressRow(i, opts.wide()) },
+	}, name, opts)
+}
 
+// ingressHeaders and ingressRow are the columns of get ingresses, which watch shares.
+func ingressHeaders(wide bool) []string {
 	headers := []string{"NAME", "CLASS", "HOSTS", "ADDRESS", "PORTS", "AGE"}
-	if showNamespace {
-		headers = []string{"NAMESPACE", "NAME", "CLASS", "HOSTS", "ADDRESS", "PORTS", "AGE"}
+	if wide {
+		headers = append(headers, "BACKENDS")
 	}
-	table := output.NewTable(headers)
-
-	for _, ing := range ingresses {
-		i, ok := ing.(*networkingv1.Ingress)
-		if !ok {
-			continue
-		}
+	return headers
+}
 
-		age := getAge(i.CreationTimestamp)
+func ingressRow(i *networkingv1.Ingress, wide bool) []string {
+	age := getAge(i.CreationTimestamp)
 
-		// Get ingress class
-		class := NoneValue
-		if i.Spec.IngressClassName != nil {
-			class = *i.Spec.IngressClassName
-		} else if i.Annotations["kubernetes.io/ingress.class"] != "" {
-			class = i.Annotations["kubernetes.io/ingress.class"]
-		}
+	// Get ingress class
+	class := NoneValue
+	if i.Spec.IngressClassName != nil {
+		class = *i.Spec.IngressClassName
+	} else if i.Annotations["kubernetes.io/ingress.class"] != "" {
+		class = i.Annotations["kubernetes.io/ingress.class"]
+	}
 
-		// Get hosts (optimized: pre-allocate slice capacity)
-		hosts := make([]string, 0, len(i.Spec.Rules))
-		for _, rule := range i.Spec.Rules {
-			if rule.Host != "" {
-				hosts = append(hosts, rule.Host)
-			}
+	// Get hosts (optimized: pre-allocate slice capacity)
+	hosts := make([]string, 0, len(i.Spec.Rules))
+	for _, rule := range i.Spec.Rules {
+		if rule.Host != "" {
+			hosts = append(hosts, rule.Host)
 		}
-		hostsStr := NoneValue
-		if len(hosts) > 0 {
-			hostsStr = hosts[0]
-			if len(hosts) > 1 {
-				hostsStr = fmt.Sprintf("%s +%d more", hostsStr, len(hosts)-1)
-			}
+	}
+	hostsStr := NoneValue
+	if len(hosts) > 0 {
+		hostsStr = hosts[0]
+		if len(hosts) > 1 {
+			hostsStr = fmt.Sprintf("%s +%d more", hostsStr, len(hosts)-1)
 		}
+	}
 
-		// Get address
-		address := NoneValue
-		if len(i.Status.LoadBalancer.Ingress) > 0 {
-			lb := i.Status.LoadBalancer.Ingress[0]
-			if lb.IP != "" {
-				address = lb.IP
-			} else if lb.Hostname != "" {
-				address = lb.Hostname
-			}
+	// Get address
+	address := NoneValue
+	if len(i.Status.LoadBalancer.Ingress) > 0 {
+		lb := i.Status.LoadBalancer.Ingress[0]
+		if lb.IP != "" {
+			address = lb.IP
+		} else if lb.Hostname != "" {
+			address = lb.Hostname
 		}
+	}
 
-		// Get ports (optimized: pre-allocate slice capacity)
-		ports := make([]string, 0, len(i.Spec.Rules)*2) // Estimate capacity
-		for _, rule := range i.Spec.Rules {
-			if rule.HTTP != nil {
-				for _, path := range rule.HTTP.Paths {
-					if path.Backend.Service != nil {
-						port := fmt.Sprintf("%d", path.Backend.Service.Port.Number)
-						ports = append(ports, port)
-					}
+	// Get ports (optimized: pre-allocate slice capacity)
+	ports := make([]string, 0, len(i.Spec.Rules)*2) // Estimate capacity
+	for _, rule := range i.Spec.Rules {
+		if rule.HTTP != nil {
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil {
+					port := fmt.Sprintf("%d", path.Backend.Service.Port.Number)
+					ports = append(ports, port)
 				}
 			}
 		}
-		portsStr := NoneValue
-		if len(ports) > 0 {
-			portsStr = ports[0]
-			if len(ports) > 1 {
-				portsStr = fmt.Sprintf("%s +%d more", portsStr, len(ports)-1)
-			}
+	}
+	portsStr := NoneValue
+	if len(ports) > 0 {
+		portsStr = ports[0]
+		if len(ports) > 1 {
+			portsStr = fmt.Sprintf("%s +%d more", portsStr, len(ports)-1)
 		}
+	}
 
-		row := []string{
-			i.Name,
-			class,
-			hostsStr,
-			address,
-			portsStr,
-			age,
+	row := []string{
+		i.Name,
+		class,
+		hostsStr,
+		address,
+		portsStr,
+		age,
+	}
+	if wide {
+		row = append(row, getIngressBackends(i))
+	}
+	return row
+}
+
+// getIngressBackends lists every service:port an ingress routes to, including the default backend
+func getIngressBackends(ing *networkingv1.Ingress) string {
+	backends := []string{}
+	addBackend := func(backend *networkingv1.IngressBackend) {
+		if backend == nil || backend.Service == nil {
+			return
 		}
-		if showNamespace {
-			row = []string{
-				i.Namespace,
-				i.Name,
-				class,
-				hostsStr,
-				address,
-				portsStr,
-				age,
-			}
+		port := backend.Service.Port.Name
+		if port == "" {
+			port = fmt.Sprintf("%d", backend.Service.Port.Number)
 		}
-		table.AddRow(row)
+		backends = append(backends, fmt.Sprintf("%s:%s", backend.Service.Name, port))
 	}
 
-	table.Render()
-	return nil
+	addBackend(ing.Spec.DefaultBackend)
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for i := range rule.HTTP.Paths {
+			addBackend(&rule.HTTP.Paths[i].Backend)
+		}
+	}
+	return valueOrNone(strings.Join(backends, ","))
 }
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultChunkSize is the default number of objects requested per list call.
+const DefaultChunkSize = 500
+
+// resourceLister describes how get fetches and tabulates one resource type.
+type resourceLister[T any] struct {
+	singular string
+	plural   string
+	// kind prefixes names for --show-kind, e.g. "deployment.apps"
+	kind       string
+	namespace  string
+	namespaced bool
+
+	get func(name string) (T, error)
+	// list fetches one page and returns the continue token for the next one
+	list func(opts metav1.ListOptions) ([]T, string, error)
+
+	// forOutput, when set, prepares an item for structured output, e.g. to redact secrets
+	forOutput func(item T) (interface{}, error)
+
+	headers []string
+	row     func(item T) []string
+}
+
+// printResources gets a single object by name, or lists all of them, and
+// prints the result. Plain tables are streamed page by page; structured
+// output and --sort-by need the whole list first.
+func printResources[T any](l resourceLister[T], name string, opts getOptions) error {
+	if !l.namespaced {
+		opts.showNamespace = false
+	}
+
+	if name != "" {
+		item, err := l.get(name)
+		if err != nil {
+			return errors.HandleKubernetesError(err, l.singular, name, l.namespace)
+		}
+		return printItems(l, []T{item}, true, opts)
+	}
+
+	if output.IsStructuredFormat(opts.outputFormat) || opts.sortBy != "" {
+		items, err := listAll(l, opts)
+		if err != nil {
+			return err
+		}
+		return printItems(l, items, false, opts)
+	}
+
+	table := output.NewStreamTable(opts.writer(), l.tableHeaders(opts))
+	err := listInChunks(opts, func(listOpts metav1.ListOptions) (string, error) {
+		page, continueToken, err := l.list(listOpts)
+		if err != nil {
+			return "", err
+		}
+		for _, item := range page {
+			table.AddRow(l.tableRow(item, opts))
+		}
+		table.Flush()
+		return continueToken, nil
+	})
+	if table.Len() > 0 || (err == nil && !opts.skipEmpty) {
+		table.Close()
+	}
+	if err != nil {
+		return errors.HandleKubernetesError(err, l.plural, "", l.namespace)
+	}
+	return nil
+}
+
+// listAll collects every page of a list.
+func listAll[T any](l resourceLister[T], opts getOptions) ([]T, error) {
+	var items []T
+	err := listInChunks(opts, func(listOpts metav1.ListOptions) (string, error) {
+		page, continueToken, err := l.list(listOpts)
+		items = append(items, page...)
+		return continueToken, err
+	})
+	if err != nil {
+		return nil, errors.HandleKubernetesError(err, l.plural, "", l.namespace)
+	}
+	return items, nil
+}
+
+func printItems[T any](l resourceLister[T], items []T, single bool, opts getOptions) error {
+	if err := opts.sort(items); err != nil {
+		return err
+	}
+
+	if output.IsStructuredFormat(opts.outputFormat) {
+		if l.forOutput == nil {
+			return printStructured(opts.writer(), items, single, opts.outputFormat)
+		}
+		prepared := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			obj, err := l.forOutput(item)
+			if err != nil {
+				return err
+			}
+			prepared = append(prepared, obj)
+		}
+		return printStructured(opts.writer(), prepared, single, opts.outputFormat)
+	}
+
+	table := output.NewTable(l.tableHeaders(opts))
+	for _, item := range items {
+		table.AddRow(l.tableRow(item, opts))
+	}
+	opts.render(table)
+	return nil
+}
+
+// tableHeaders adds the label columns, and the NAMESPACE column when listing
+// across namespaces.
+func (l resourceLister[T]) tableHeaders(opts getOptions) []string {
+	headers := opts.columns.headers(l.headers)
+	if !opts.showNamespace {
+		return headers
+	}
+	return append([]string{"NAMESPACE"}, headers...)
+}
+
+func (l resourceLister[T]) tableRow(item T, opts getOptions) []string {
+	obj, _ := any(item).(metav1.Object)
+	row := opts.columns.row(l.kind, obj, l.row(item))
+	if !opts.showNamespace {
+		return row
+	}
+	namespace := ""
+	if obj != nil {
+		namespace = obj.GetNamespace()
+	}
+	return append([]string{namespace}, row...)
+}
+
+// listInChunks pages through a list call --chunk-size objects at a time,
+// following continue tokens until the server reports no more pages.
+func listInChunks(opts getOptions, list func(listOpts metav1.ListOptions) (string, error)) error {
+	listOpts := opts.listOptions()
+	for {
+		continueToken, err := list(listOpts)
+		if err != nil {
+			return err
+		}
+		if continueToken == "" {
+			return nil
+		}
+		listOpts.Continue = continueToken
+	}
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pagedPodLister serves pods in pages of listOpts.Limit, counting list calls
+func pagedPodLister(pods []*corev1.Pod, calls *int) resourceLister[*corev1.Pod] {
+	return resourceLister[*corev1.Pod]{
+		singular:   "pod",
+		plural:     "pods",
+		namespace:  "default",
+		namespaced: true,
+		list: func(listOpts metav1.ListOptions) ([]*corev1.Pod, string, error) {
+			*calls++
+			start := 0
+			if listOpts.Continue != "" {
+				start, _ = strconv.Atoi(listOpts.Continue)
+			}
+			end, continueToken := len(pods), ""
+			if listOpts.Limit > 0 && start+int(listOpts.Limit) < len(pods) {
+				end = start + int(listOpts.Limit)
+				continueToken = strconv.Itoa(end)
+			}
+			return pods[start:end], continueToken, nil
+		},
+		headers: []string{"NAME"},
+		row: func(pod *corev1.Pod) []string {
+			return []string{pod.Name}
+		},
+	}
+}
+
+func testPodList(count int) []*corev1.Pod {
+	pods := make([]*corev1.Pod, 0, count)
+	for i := 0; i < count; i++ {
+		pods = append(pods, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: "default"}})
+	}
+	return pods
+}
+
+func TestPrintResourcesChunked(t *testing.T) {
+	calls := 0
+	lister := pagedPodLister(testPodList(5), &calls)
+
+	var buf bytes.Buffer
+	if err := printResources(lister, "", getOptions{outputFormat: "table", chunkSize: 2, out: &buf}); err != nil {
+		t.Fatalf("printResources failed: %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 list calls for 5 pods in chunks of 2, got %d", calls)
+	}
+	for i := 0; i < 5; i++ {
+		if !strings.Contains(buf.String(), fmt.Sprintf("pod-%d", i)) {
+			t.Errorf("Output is missing pod-%d:\n%s", i, buf.String())
+		}
+	}
+}
+
+func TestPrintResourcesUnchunked(t *testing.T) {
+	calls := 0
+	lister := pagedPodLister(testPodList(5), &calls)
+
+	var buf bytes.Buffer
+	if err := printResources(lister, "", getOptions{outputFormat: "table", out: &buf}); err != nil {
+		t.Fatalf("printResources failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single list call without --chunk-size, got %d", calls)
+	}
+}
+
+func TestPrintResourcesSortsAcrossChunks(t *testing.T) {
+	calls := 0
+	lister := pagedPodLister(testPodList(5), &calls)
+
+	var buf bytes.Buffer
+	opts := getOptions{outputFormat: "jsonpath={.items[*].metadata.name}", chunkSize: 2, sortBy: ".metadata.name", reverse: true, out: &buf}
+	if err := printResources(lister, "", opts); err != nil {
+		t.Fatalf("printResources failed: %v", err)
+	}
+
+	expected := "pod-4 pod-3 pod-2 pod-1 pod-0"
+	if buf.String() != expected {
+		t.Errorf("printResources() = %q, expected %q", buf.String(), expected)
+	}
+}
+
+func TestPrintResourcesListError(t *testing.T) {
+	lister := resourceLister[*corev1.Pod]{
+		singular: "pod",
+		plural:   "pods",
+		list: func(metav1.ListOptions) ([]*corev1.Pod, string, error) {
+			return nil, "", fmt.Errorf("connection refused")
+		},
+		headers: []string{"NAME"},
+		row:     func(pod *corev1.Pod) []string { return []string{pod.Name} },
+	}
+
+	var buf bytes.Buffer
+	if err := printResources(lister, "", getOptions{outputFormat: "table", out: &buf}); err == nil {
+		t.Error("printResources should fail when listing fails")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("No table should be printed when the first page fails, got:\n%s", buf.String())
+	}
+}
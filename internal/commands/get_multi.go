@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// allResourceTypes mirrors the types kubectl includes in the "all" category.
+var allResourceTypes = []string{
+	"pods",
+	"replicationcontrollers",
+	"services",
+	"daemonsets",
+	"deployments",
+	"replicasets",
+	"statefulsets",
+	"horizontalpodautoscalers",
+	"cronjobs",
+	"jobs",
+}
+
+// getRequest is one resource type to get, optionally narrowed to a single object.
+type getRequest struct {
+	resourceType string
+	name         string
+}
+
+// parseGetArgs expands the get arguments into requests. Arguments are either
+// comma-separated types followed by names ("po,svc web"), or all in
+// type/name form ("deploy/api svc/api").
+func parseGetArgs(args []string) ([]getRequest, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("a resource type is required")
+	}
+
+	if strings.Contains(args[0], "/") {
+		requests := make([]getRequest, 0, len(args))
+		for _, arg := range args {
+			resourceType, name, found := strings.Cut(arg, "/")
+			if !found {
+				return nil, fmt.Errorf("there is no need to specify a resource type as a separate argument when passing arguments in resource/name form (e.g. 'k8ctl get resource/<resource_name>' instead of 'k8ctl get resource resource/<resource_name>')")
+			}
+			if resourceType == "" || name == "" {
+				return nil, fmt.Errorf("arguments in resource/name form must have a single resource and name: %s", arg)
+			}
+			requests = append(requests, getRequest{resourceType: expandResourceType(resourceType), name: name})
+		}
+		return requests, nil
+	}
+
+	types := []string{}
+	for _, resourceType := range strings.Split(args[0], ",") {
+		switch resourceType {
+		case "":
+			continue
+		case "all":
+			types = append(types, allResourceTypes...)
+		default:
+			types = append(types, expandResourceType(resourceType))
+		}
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("a resource type is required")
+	}
+
+	names := args[1:]
+	for _, name := range names {
+		if strings.Contains(name, "/") {
+			return nil, fmt.Errorf("there is no need to specify a resource type as a separate argument when passing arguments in resource/name form")
+		}
+	}
+
+	requests := make([]getRequest, 0, len(types)*max(len(names), 1))
+	for _, resourceType := range types {
+		if len(names) == 0 {
+			requests = append(requests, getRequest{resourceType: resourceType})
+			continue
+		}
+		for _, name := range names {
+			requests = append(requests, getRequest{resourceType: resourceType, name: name})
+		}
+	}
+	return requests, nil
+}
+
+// expandResourceType resolves the short and singular names the typed getters
+// know about.
+func expandResourceType(resourceType string) string {
+	if expanded, ok := resourceShortcuts[strings.ToLower(resourceType)]; ok {
+		return expanded
+	}
+	return resourceType
+}
+
+// getMultiple fetches every request concurrently and prints one titled table
+// per type, in the order requested. Types without any objects are skipped.
+func getMultiple(client kubernetes.Interface, requests []getRequest, namespace string, opts getOptions) error {
+	buffers := make([]bytes.Buffer, len(requests))
+	errs := make([]error, len(requests))
+
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		wg.Add(1)
+		go func(i int, req getRequest) {
+			defer wg.Done()
+			reqOpts := opts
+			reqOpts.out = &buffers[i]
+			reqOpts.skipEmpty = req.name == ""
+			errs[i] = getByType(client, req.resourceType, namespace, req.name, reqOpts)
+		}(i, req)
+	}
+	wg.Wait()
+
+	w := opts.writer()
+	printed, failed := 0, 0
+	lastTitle := ""
+	for i, req := range requests {
+		if errs[i] != nil {
+			errors.PrintError(errs[i])
+			failed++
+			continue
+		}
+		if buffers[i].Len() == 0 {
+			continue
+		}
+
+		// Requests for several names of one type share a title
+		if req.resourceType != lastTitle {
+			if printed > 0 {
+				_, _ = fmt.Fprintln(w)
+			}
+			_, _ = output.HeaderColor.Fprintln(w, strings.ToUpper(req.resourceType))
+			lastTitle = req.resourceType
+		}
+		_, _ = buffers[i].WriteTo(w)
+		printed++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to get %d of %d requested resources", failed, len(requests))
+	}
+	if printed == 0 {
+		_, _ = output.Info.Fprintln(w, noResourcesMessage(namespace))
+	}
+	return nil
+}
+
+// getMultipleStructured fetches every request concurrently through the dynamic
+// client and prints all objects as a single List.
+func getMultipleStructured(client dynamic.Interface, mapper meta.RESTMapper, requests []getRequest, namespace string, opts getOptions) error {
+	results := make([][]map[string]interface{}, len(requests))
+	errs := make([]error, len(requests))
+
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		wg.Add(1)
+		go func(i int, req getRequest) {
+			defer wg.Done()
+			res, err := lookupResource(mapper, req.resourceType)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			items, err := fetchUnstructured(client, res, namespace, req.name, opts)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for _, item := range items {
+				results[i] = append(results[i], item.Object)
+			}
+		}(i, req)
+	}
+	wg.Wait()
+
+	// A partial List would be misleading to scripts, so any failure fails the command
+	items := []map[string]interface{}{}
+	for i := range requests {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		items = append(items, results[i]...)
+	}
+
+	if err := opts.sort(items); err != nil {
+		return err
+	}
+	if opts.decode {
+		for _, item := range items {
+			if err := redactSecretObject(item, true); err != nil {
+				return err
+			}
+		}
+	}
+	return printStructured(opts.writer(), items, false, opts.outputFormat)
+}
+
+func noResourcesMessage(namespace string) string {
+	if namespace == "" {
+		return "No resources found"
+	}
+	return fmt.Sprintf("No resources found in %s namespace.", namespace)
+}
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseGetArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []getRequest
+	}{
+		{
+			name:     "single type",
+			args:     []string{"po"},
+			expected: []getRequest{{resourceType: "pods"}},
+		},
+		{
+			name:     "single type with name",
+			args:     []string{"pods", "web"},
+			expected: []getRequest{{resourceType: "pods", name: "web"}},
+		},
+		{
+			name:     "comma separated types",
+			args:     []string{"po,svc,deploy"},
+			expected: []getRequest{{resourceType: "pods"}, {resourceType: "services"}, {resourceType: "deployments"}},
+		},
+		{
+			name: "names apply to every type",
+			args: []string{"deploy,svc", "api", "web"},
+			expected: []getRequest{
+				{resourceType: "deployments", name: "api"},
+				{resourceType: "deployments", name: "web"},
+				{resourceType: "services", name: "api"},
+				{resourceType: "services", name: "web"},
+			},
+		},
+		{
+			name:     "type/name pairs",
+			args:     []string{"deploy/api", "svc/api"},
+			expected: []getRequest{{resourceType: "deployments", name: "api"}, {resourceType: "services", name: "api"}},
+		},
+		{
+			name:     "singular names",
+			args:     []string{"Pod,deployment,service"},
+			expected: []getRequest{{resourceType: "pods"}, {resourceType: "deployments"}, {resourceType: "services"}},
+		},
+		{
+			name:     "singular type/name",
+			args:     []string{"secret/db"},
+			expected: []getRequest{{resourceType: "secrets", name: "db"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, err := parseGetArgs(tt.args)
+			if err != nil {
+				t.Fatalf("parseGetArgs(%v) failed: %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(requests, tt.expected) {
+				t.Errorf("parseGetArgs(%v) = %+v, expected %+v", tt.args, requests, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseGetArgsAll(t *testing.T) {
+	requests, err := parseGetArgs([]string{"all,cm"})
+	if err != nil {
+		t.Fatalf("parseGetArgs failed: %v", err)
+	}
+	if len(requests) != len(allResourceTypes)+1 {
+		t.Fatalf("Expected %d requests, got %d", len(allResourceTypes)+1, len(requests))
+	}
+	if requests[len(requests)-1].resourceType != "configmaps" {
+		t.Errorf("Expected configmaps after the all category, got %s", requests[len(requests)-1].resourceType)
+	}
+}
+
+func TestParseGetArgsErrors(t *testing.T) {
+	invalid := [][]string{
+		{","},
+		{"deploy/api", "svc"},
+		{"deploy", "svc/api"},
+		{"deploy/"},
+	}
+	for _, args := range invalid {
+		if _, err := parseGetArgs(args); err == nil {
+			t.Errorf("parseGetArgs(%v) should fail", args)
+		}
+	}
+}
+
+func TestGetMultiple(t *testing.T) {
+	replicas := int32(1)
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "default"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+	)
+
+	requests := []getRequest{{resourceType: "pods"}, {resourceType: "services"}, {resourceType: "deployments"}}
+
+	var buf bytes.Buffer
+	if err := getMultiple(clientset, requests, "default", getOptions{outputFormat: "table", out: &buf}); err != nil {
+		t.Fatalf("getMultiple failed: %v", err)
+	}
+
+	result := buf.String()
+	pods := strings.Index(result, "PODS")
+	deployments := strings.Index(result, "DEPLOYMENTS")
+	if pods < 0 || deployments < 0 || pods > deployments {
+		t.Errorf("Expected PODS then DEPLOYMENTS tables, got:\n%s", result)
+	}
+	if strings.Contains(result, "SERVICES") {
+		t.Errorf("Empty types should be skipped, got:\n%s", result)
+	}
+}
+
+func TestGetMultipleReportsFailures(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "default"}},
+	)
+
+	requests := []getRequest{{resourceType: "pods"}, {resourceType: "deployments", name: "missing"}}
+
+	var buf bytes.Buffer
+	if err := getMultiple(clientset, requests, "default", getOptions{outputFormat: "table", out: &buf}); err == nil {
+		t.Error("getMultiple should fail when a request fails")
+	}
+	if !strings.Contains(buf.String(), "api-0") {
+		t.Errorf("Successful requests should still be printed, got:\n%s", buf.String())
+	}
+}
+
+func TestGetMultipleStructured(t *testing.T) {
+	client := newCertificateClient()
+	requests := []getRequest{{resourceType: "certificates"}, {resourceType: "certificates.cert-manager.io", name: "api-tls"}}
+
+	var buf bytes.Buffer
+	err := getMultipleStructured(client, newTestRESTMapper(), requests, "default", getOptions{outputFormat: "json", out: &buf})
+	if err != nil {
+		t.Fatalf("getMultipleStructured failed: %v", err)
+	}
+
+	var list struct {
+		Kind  string                   `json:"kind"`
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if list.Kind != "List" || len(list.Items) != 2 {
+		t.Errorf("Expected a List with 2 items, got kind %q with %d items", list.Kind, len(list.Items))
+	}
+
+	requests = append(requests, getRequest{resourceType: "doesnotexist"})
+	if err := getMultipleStructured(client, newTestRESTMapper(), requests, "default", getOptions{outputFormat: "json", out: &buf}); err == nil {
+		t.Error("getMultipleStructured should fail for unknown resource types")
+	}
+}
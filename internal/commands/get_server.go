@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/k8s"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// tableAcceptHeader asks the API server to render responses as meta.k8s.io Tables,
+// falling back to plain JSON for servers that do not support it.
+const tableAcceptHeader = "application/json;as=Table;v=v1;g=meta.k8s.io," +
+	"application/json;as=Table;v=v1beta1;g=meta.k8s.io," +
+	"application/json"
+
+// getServerPrinted renders any resource type using the columns computed by the API server.
+func getServerPrinted(resourceType, namespace, name string, opts getOptions) error {
+	mapper, err := k8s.GetRESTMapper()
+	if err != nil {
+		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
+	}
+
+	res, err := lookupResource(mapper, resourceType)
+	if err != nil {
+		return err
+	}
+
+	client, err := k8s.GetClient()
+	if err != nil {
+		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
+	}
+
+	// The discovery REST client is unversioned, so it can address any group
+	return getServerTable(client.Discovery().RESTClient(), res, namespace, name, opts)
+}
+
+func getServerTable(client rest.Interface, res *resolvedResource, namespace, name string, opts getOptions) error {
+	if !res.namespaced {
+		namespace = ""
+		opts.showNamespace = false
+	}
+
+	// Sorting needs the full objects; otherwise metadata is enough for the namespace column
+	includeObject := metav1.IncludeMetadata
+	if opts.sortBy != "" {
+		includeObject = metav1.IncludeObject
+	}
+
+	// Pages are merged into one table so the columns are sized across all rows
+	var serverTable *metav1.Table
+	continueToken := ""
+	for {
+		page, err := getServerTablePage(client, res, namespace, name, includeObject, continueToken, opts)
+		if err != nil {
+			return err
+		}
+		if serverTable == nil {
+			serverTable = page
+		} else {
+			serverTable.Rows = append(serverTable.Rows, page.Rows...)
+		}
+
+		continueToken = page.Continue
+		if continueToken == "" {
+			break
+		}
+	}
+
+	if opts.sortBy != "" {
+		if err := output.SortTableRows(serverTable.Rows, opts.sortBy, opts.reverse); err != nil {
+			return errors.WrapError(err, "Failed to sort by "+opts.sortBy)
+		}
+	}
+
+	opts.render(buildServerTable(serverTable, displayKind(res.kind, res.gvr.Group), opts))
+	return nil
+}
+
+// getServerTablePage requests one chunk of a server-side table.
+func getServerTablePage(client rest.Interface, res *resolvedResource, namespace, name string, includeObject metav1.IncludeObjectPolicy, continueToken string, opts getOptions) (*metav1.Table, error) {
+	req := client.Get().
+		AbsPath(resourcePath(res, namespace, name)...).
+		SetHeader("Accept", tableAcceptHeader).
+		Param("includeObject", string(includeObject))
+	if opts.labelSelector != "" {
+		req = req.Param("labelSelector", opts.labelSelector)
+	}
+	if opts.fieldSelector != "" {
+		req = req.Param("fieldSelector", opts.fieldSelector)
+	}
+	if name == "" && opts.chunkSize > 0 {
+		req = req.Param("limit", strconv.FormatInt(opts.chunkSize, 10))
+	}
+	if continueToken != "" {
+		req = req.Param("continue", continueToken)
+	}
+
+	raw, err := req.Do(context.Background()).Raw()
+	if err != nil {
+		if name != "" {
+			return nil, errors.HandleKubernetesError(err, strings.ToLower(res.kind), name, namespace)
+		}
+		return nil, errors.HandleKubernetesError(err, res.gvr.Resource, "", namespace)
+	}
+
+	page := &metav1.Table{}
+	if err := json.Unmarshal(raw, page); err != nil {
+		return nil, fmt.Errorf("failed to decode server table: %w", err)
+	}
+	if page.Kind != "Table" {
+		return nil, errors.WrapError(
+			fmt.Errorf("server returned %q instead of Table", page.Kind),
+			fmt.Sprintf("The API server cannot render '%s' as a table", res.gvr.Resource),
+		)
+	}
+	return page, nil
+}
+
+// resourcePath builds the REST path of a collection, or of a single object when name is set.
+func resourcePath(res *resolvedResource, namespace, name string) []string {
+	segments := []string{"/api", res.gvr.Version}
+	if res.gvr.Group != "" {
+		segments = []string{"/apis", res.gvr.Group, res.gvr.Version}
+	}
+	if res.namespaced && namespace != "" {
+		segments = append(segments, "namespaces", namespace)
+	}
+	segments = append(segments, res.gvr.Resource)
+	if name != "" {
+		segments = append(segments, name)
+	}
+	return segments
+}
+
+func buildServerTable(serverTable *metav1.Table, kind string, opts getOptions) *output.Table {
+	// Like kubectl, only priority 0 columns are shown unless wide output is requested
+	columns := make([]int, 0, len(serverTable.ColumnDefinitions))
+	headers := make([]string, 0, len(serverTable.ColumnDefinitions))
+	for i, col := range serverTable.ColumnDefinitions {
+		if col.Priority > 0 && !opts.wide() {
+			continue
+		}
+		columns = append(columns, i)
+		headers = append(headers, strings.ToUpper(col.Name))
+	}
+	headers = opts.columns.headers(headers)
+	if opts.showNamespace {
+		headers = append([]string{"NAMESPACE"}, headers...)
+	}
+
+	table := output.NewTable(headers)
+	for _, row := range serverTable.Rows {
+		cells := make([]string, 0, len(headers))
+		for _, i := range columns {
+			if i >= len(row.Cells) {
+				cells = append(cells, "")
+				continue
+			}
+			cells = append(cells, formatColumnValue(row.Cells[i], serverTable.ColumnDefinitions[i].Type))
+		}
+		metadata := tableRowMetadata(row)
+		cells = opts.columns.row(kind, metadata, cells)
+		if opts.showNamespace {
+			cells = append([]string{metadata.Namespace}, cells...)
+		}
+		table.AddRow(cells)
+	}
+	return table
+}
+
+// tableRowMetadata extracts the metadata the server embeds in each row.
+func tableRowMetadata(row metav1.TableRow) *metav1.PartialObjectMetadata {
+	partial := &metav1.PartialObjectMetadata{}
+	if len(row.Object.Raw) > 0 {
+		_ = json.Unmarshal(row.Object.Raw, partial)
+	}
+	return partial
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+)
+
+const testPodTable = `{
+  "kind": "Table",
+  "apiVersion": "meta.k8s.io/v1",
+  "columnDefinitions": [
+    {"name": "Name", "type": "string", "priority": 0},
+    {"name": "Ready", "type": "string", "priority": 0},
+    {"name": "Status", "type": "string", "priority": 0},
+    {"name": "Restarts", "type": "integer", "priority": 0},
+    {"name": "Age", "type": "string", "priority": 0},
+    {"name": "IP", "type": "string", "priority": 1}
+  ],
+  "rows": [
+    {
+      "cells": ["api-0", "0/1", "CrashLoopBackOff", 4, "5m", "10.0.0.4"],
+      "object": {"kind": "PartialObjectMetadata", "apiVersion": "meta.k8s.io/v1", "metadata": {"name": "api-0", "namespace": "prod"}}
+    }
+  ]
+}`
+
+func newTableRESTClient(t *testing.T, body string, wantPath string) *restfake.RESTClient {
+	return &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != wantPath {
+				t.Errorf("request path = %q, expected %q", req.URL.Path, wantPath)
+			}
+			if !strings.Contains(req.Header.Get("Accept"), "as=Table") {
+				t.Errorf("Accept header %q does not request a Table", req.Header.Get("Accept"))
+			}
+			header := http.Header{}
+			header.Set("Content-Type", "application/json")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}, nil
+		}),
+	}
+}
+
+func TestResourcePath(t *testing.T) {
+	pods := &resolvedResource{gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, namespaced: true}
+	deployments := &resolvedResource{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, namespaced: true}
+	nodes := &resolvedResource{gvr: schema.GroupVersionResource{Version: "v1", Resource: "nodes"}}
+
+	tests := []struct {
+		name      string
+		res       *resolvedResource
+		namespace string
+		object    string
+		expected  string
+	}{
+		{"core namespaced", pods, "default", "", "/api/v1/namespaces/default/pods"},
+		{"core all namespaces", pods, "", "", "/api/v1/pods"},
+		{"group with name", deployments, "prod", "api", "/apis/apps/v1/namespaces/prod/deployments/api"},
+		{"cluster scoped", nodes, "", "node-1", "/api/v1/nodes/node-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := path.Join(resourcePath(tt.res, tt.namespace, tt.object)...)
+			if result != tt.expected {
+				t.Errorf("resourcePath() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetServerTable(t *testing.T) {
+	res := &resolvedResource{gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, kind: "Pod", namespaced: true}
+
+	client := newTableRESTClient(t, testPodTable, "/api/v1/pods")
+	if err := getServerTable(client, res, "", "", getOptions{outputFormat: "table", showNamespace: true}); err != nil {
+		t.Errorf("getServerTable failed: %v", err)
+	}
+}
+
+func TestGetServerTableRejectsNonTable(t *testing.T) {
+	res := &resolvedResource{gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, kind: "Pod", namespaced: true}
+
+	client := newTableRESTClient(t, `{"kind": "PodList", "apiVersion": "v1", "items": []}`, "/api/v1/namespaces/default/pods")
+	if err := getServerTable(client, res, "default", "", getOptions{outputFormat: "table"}); err == nil {
+		t.Error("getServerTable should fail when the server does not return a Table")
+	}
+}
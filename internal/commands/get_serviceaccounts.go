@@ -3,71 +3,65 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"github.com/robertusnegoro/k8ctl/internal/errors"
-	"github.com/robertusnegoro/k8ctl/internal/output"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-func getServiceAccounts(client kubernetes.Interface, namespace, name, outputFormat string, showNamespace bool) error {
-	var serviceAccounts []interface{}
+func getServiceAccounts(client kubernetes.Interface, namespace, name string, opts getOptions) error {
+	serviceAccounts := client.CoreV1().ServiceAccounts(namespace)
 
-	if name != "" {
-		sa, err := client.CoreV1().ServiceAccounts(namespace).Get(context.Background(), name, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "serviceaccount", name, namespace)
-		}
-		serviceAccounts = []interface{}{sa}
-	} else {
-		saList, err := client.CoreV1().ServiceAccounts(namespace).List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "serviceaccounts", "", namespace)
-		}
-		for i := range saList.Items {
-			serviceAccounts = append(serviceAccounts, &saList.Items[i])
-		}
-	}
-
-	if outputFormat == OutputFormatJSON {
-		return output.FormatJSON(serviceAccounts)
-	}
-	if outputFormat == OutputFormatYAML {
-		return output.FormatYAML(serviceAccounts)
-	}
+	return printResources(resourceLister[*corev1.ServiceAccount]{
+		singular:   "serviceaccount",
+		kind:       "serviceaccount",
+		plural:     "serviceaccounts",
+		namespace:  namespace,
+		namespaced: true,
+		get: func(name string) (*corev1.ServiceAccount, error) {
+			return serviceAccounts.Get(context.Background(), name, metav1.GetOptions{})
+		},
+		list: func(listOpts metav1.ListOptions) ([]*corev1.ServiceAccount, string, error) {
+			saList, err := serviceAccounts.List(context.Background(), listOpts)
+			if err != nil {
+				return nil, "", err
+			}
+			items := make([]*corev1.ServiceAccount, 0, len(saList.Items))
+			for i := range saList.Items {
+				items = append(items, &saList.Items[i])
+			}
+			return items, saList.Continue, nil
+		},
+		headers: serviceAccountHeaders(opts.wide()),
+		row:     func(s *corev1.ServiceAccount) []string { return serviceAccountRow(s, opts.wide()) },
+	}, name, opts)
+}
 
+// serviceAccountHeaders and serviceAccountRow are the columns of get serviceaccounts, which watch shares.
+func serviceAccountHeaders(wide bool) []string {
 	headers := []string{"NAME", "SECRETS", "AGE"}
-	if showNamespace {
-		headers = []string{"NAMESPACE", "NAME", "SECRETS", "AGE"}
+	if wide {
+		headers = append(headers, "IMAGE PULL SECRETS")
 	}
-	table := output.NewTable(headers)
-
-	for _, sa := range serviceAccounts {
-		s, ok := sa.(*corev1.ServiceAccount)
-		if !ok {
-			continue
-		}
+	return headers
+}
 
-		age := getAge(s.CreationTimestamp)
-		secretsCount := fmt.Sprintf("%d", len(s.Secrets))
+func serviceAccountRow(s *corev1.ServiceAccount, wide bool) []string {
+	age := getAge(s.CreationTimestamp)
+	secretsCount := fmt.Sprintf("%d", len(s.Secrets))
 
-		row := []string{
-			s.Name,
-			secretsCount,
-			age,
-		}
-		if showNamespace {
-			row = []string{
-				s.Namespace,
-				s.Name,
-				secretsCount,
-				age,
-			}
+	row := []string{
+		s.Name,
+		secretsCount,
+		age,
+	}
+	if wide {
+		pullSecrets := make([]string, 0, len(s.ImagePullSecrets))
+		for _, ref := range s.ImagePullSecrets {
+			pullSecrets = append(pullSecrets, ref.Name)
 		}
-		table.AddRow(row)
+		row = append(row, valueOrNone(strings.Join(pullSecrets, ",")))
 	}
-
-	table.Render()
-	return nil
+	return row
 }
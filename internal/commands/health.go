@@ -16,6 +16,7 @@ import (
 // NewHealthCommand creates a new health command for displaying cluster health dashboard.
 func NewHealthCommand() *cobra.Command {
 	var namespace string
+	var listOpts metav1.ListOptions
 
 	cmd := &cobra.Command{
 		Use:   "health",
@@ -25,16 +26,18 @@ func NewHealthCommand() *cobra.Command {
 - Pod health by namespace
 - Resource status summary`,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return runHealth(nil, namespace)
+			return runHealth(nil, namespace, listOpts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (overrides config, empty for all)")
+	cmd.Flags().StringVarP(&listOpts.LabelSelector, "selector", "l", "", "Label selector to filter pods on (e.g. app=api)")
+	cmd.Flags().StringVar(&listOpts.FieldSelector, "field-selector", "", "Field selector to filter pods on (e.g. status.phase=Failed)")
 
 	return cmd
 }
 
-func runHealth(_ *cobra.Command, namespace string) error {
+func runHealth(_ *cobra.Command, namespace string, listOpts metav1.ListOptions) error {
 	client, err := k8s.GetClient()
 	if err != nil {
 		return fmt.Errorf("failed to get Kubernetes client: %w", err)
@@ -54,12 +57,12 @@ func runHealth(_ *cobra.Command, namespace string) error {
 			return fmt.Errorf("failed to list namespaces: %w", err)
 		}
 		for _, ns := range namespaces {
-			if err := displayPodHealth(client, ns); err != nil {
+			if err := displayPodHealth(client, ns, listOpts); err != nil {
 				fmt.Printf("Error displaying pods in %s: %v\n", ns, err)
 			}
 		}
 	} else {
-		if err := displayPodHealth(client, namespace); err != nil {
+		if err := displayPodHealth(client, namespace, listOpts); err != nil {
 			return fmt.Errorf("failed to display pod health: %w", err)
 		}
 	}
@@ -112,8 +115,8 @@ func displayNodes(client *kubernetes.Clientset) error {
 	return nil
 }
 
-func displayPodHealth(client *kubernetes.Clientset, namespace string) error {
-	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+func displayPodHealth(client *kubernetes.Clientset, namespace string, listOpts metav1.ListOptions) error {
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), listOpts)
 	if err != nil {
 		return err
 	}
@@ -143,13 +146,13 @@ func displayPodHealth(client *kubernetes.Clientset, namespace string) error {
 			fmt.Sprintf("%d", restarts),
 		})
 
-		switch status {
-		case "Running":
+		switch {
+		case output.IsFailedStatus(status):
+			failed++
+		case status == "Running":
 			running++
-		case "Pending":
+		case status == "Pending", status == "ContainerCreating", strings.HasPrefix(status, "Init:"):
 			pending++
-		case "Failed", "Error":
-			failed++
 		}
 	}
 
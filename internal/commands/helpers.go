@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -20,11 +22,130 @@ func getReadyContainers(pod *corev1.Pod) int {
 	return ready
 }
 
+// getPodStatus computes the STATUS column the same way kubectl does: init
+// container progress, container waiting/terminated reasons and deletion all
+// take precedence over the raw pod phase.
 func getPodStatus(pod *corev1.Pod) string {
-	if pod.Status.Phase != "" {
-		return string(pod.Status.Phase)
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
 	}
-	return "Unknown"
+
+	initContainers := make(map[string]*corev1.Container, len(pod.Spec.InitContainers))
+	for i := range pod.Spec.InitContainers {
+		initContainers[pod.Spec.InitContainers[i].Name] = &pod.Spec.InitContainers[i]
+	}
+
+	initializing := false
+	for i := range pod.Status.InitContainerStatuses {
+		container := pod.Status.InitContainerStatuses[i]
+		switch {
+		case container.State.Terminated != nil && container.State.Terminated.ExitCode == 0:
+			continue
+		case isRestartableInitContainer(initContainers[container.Name]) &&
+			container.Started != nil && *container.Started:
+			// Sidecar containers keep running alongside the main containers
+			continue
+		case container.State.Terminated != nil:
+			if container.State.Terminated.Reason == "" {
+				if container.State.Terminated.Signal != 0 {
+					reason = fmt.Sprintf("Init:Signal:%d", container.State.Terminated.Signal)
+				} else {
+					reason = fmt.Sprintf("Init:ExitCode:%d", container.State.Terminated.ExitCode)
+				}
+			} else {
+				reason = "Init:" + container.State.Terminated.Reason
+			}
+			initializing = true
+		case container.State.Waiting != nil && container.State.Waiting.Reason != "" &&
+			container.State.Waiting.Reason != "PodInitializing":
+			reason = "Init:" + container.State.Waiting.Reason
+			initializing = true
+		default:
+			reason = fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+			initializing = true
+		}
+		break
+	}
+
+	if !initializing {
+		hasRunning := false
+		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+			container := pod.Status.ContainerStatuses[i]
+			switch {
+			case container.State.Waiting != nil && container.State.Waiting.Reason != "":
+				reason = container.State.Waiting.Reason
+			case container.State.Terminated != nil && container.State.Terminated.Reason != "":
+				reason = container.State.Terminated.Reason
+			case container.State.Terminated != nil:
+				if container.State.Terminated.Signal != 0 {
+					reason = fmt.Sprintf("Signal:%d", container.State.Terminated.Signal)
+				} else {
+					reason = fmt.Sprintf("ExitCode:%d", container.State.Terminated.ExitCode)
+				}
+			case container.Ready && container.State.Running != nil:
+				hasRunning = true
+			}
+		}
+
+		// A completed container next to a running one means the pod is still running
+		if reason == "Completed" && hasRunning {
+			if hasPodReadyCondition(pod.Status.Conditions) {
+				reason = "Running"
+			} else {
+				reason = "NotReady"
+			}
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		if pod.Status.Reason == "NodeLost" {
+			reason = "Unknown"
+		} else {
+			reason = "Terminating"
+		}
+	}
+
+	if reason == "" {
+		return "Unknown"
+	}
+	return reason
+}
+
+func isRestartableInitContainer(container *corev1.Container) bool {
+	if container == nil || container.RestartPolicy == nil {
+		return false
+	}
+	return *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+func hasPodReadyCondition(conditions []corev1.PodCondition) bool {
+	for _, condition := range conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// getReadinessGates reports how many of the pod's readiness gates are satisfied
+func getReadinessGates(pod *corev1.Pod) string {
+	if len(pod.Spec.ReadinessGates) == 0 {
+		return NoneValue
+	}
+
+	trueConditions := 0
+	for _, gate := range pod.Spec.ReadinessGates {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == gate.ConditionType {
+				if condition.Status == corev1.ConditionTrue {
+					trueConditions++
+				}
+				break
+			}
+		}
+	}
+	return fmt.Sprintf("%d/%d", trueConditions, len(pod.Spec.ReadinessGates))
 }
 
 func getRestartCount(pod *corev1.Pod) int32 {
@@ -36,11 +157,16 @@ func getRestartCount(pod *corev1.Pod) int32 {
 }
 
 func getAge(creationTime metav1.Time) string {
+	return ageAt(creationTime, time.Now())
+}
+
+// ageAt formats the age of an object created at creationTime as of now.
+func ageAt(creationTime metav1.Time, now time.Time) string {
 	if creationTime.IsZero() {
 		return "<unknown>"
 	}
 
-	duration := time.Since(creationTime.Time)
+	duration := now.Sub(creationTime.Time)
 
 	switch {
 	case duration < time.Minute:
@@ -54,3 +180,57 @@ func getAge(creationTime metav1.Time) string {
 		return fmt.Sprintf("%dd", days)
 	}
 }
+
+// Helper functions for wide output
+
+func getContainersAndImages(containers []corev1.Container) (names, images string) {
+	nameList := make([]string, 0, len(containers))
+	imageList := make([]string, 0, len(containers))
+	for i := range containers {
+		nameList = append(nameList, containers[i].Name)
+		imageList = append(imageList, containers[i].Image)
+	}
+	return valueOrNone(strings.Join(nameList, ",")), valueOrNone(strings.Join(imageList, ","))
+}
+
+func getNodeAddress(node *corev1.Node, addressType corev1.NodeAddressType) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == addressType {
+			return address.Address
+		}
+	}
+	return NoneValue
+}
+
+func joinSortedOrNone(values []string) string {
+	sort.Strings(values)
+	return valueOrNone(strings.Join(values, ","))
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return NoneValue
+	}
+	return value
+}
+
+// getDesiredReplicas returns the replicas a workload asks for; the API
+// server defaults an unset count to 1.
+func getDesiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// getNodeRoles lists the roles set through node-role.kubernetes.io/ labels, sorted
+func getNodeRoles(node *corev1.Node) []string {
+	roles := []string{}
+	for label := range node.Labels {
+		if strings.HasPrefix(label, "node-role.kubernetes.io/") {
+			roles = append(roles, strings.TrimPrefix(label, "node-role.kubernetes.io/"))
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
@@ -0,0 +1,256 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetPodStatus(t *testing.T) {
+	now := metav1.Now()
+	started := true
+	always := corev1.ContainerRestartPolicyAlways
+
+	tests := []struct {
+		name     string
+		spec     corev1.PodSpec
+		status   corev1.PodStatus
+		deleted  bool
+		expected string
+	}{
+		{
+			name:     "running",
+			status:   corev1.PodStatus{Phase: corev1.PodRunning},
+			expected: "Running",
+		},
+		{
+			name:     "no phase",
+			expected: "Unknown",
+		},
+		{
+			name: "crash loop",
+			status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				},
+			},
+			expected: "CrashLoopBackOff",
+		},
+		{
+			name: "image pull backoff",
+			status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+				},
+			},
+			expected: "ImagePullBackOff",
+		},
+		{
+			name: "oom killed",
+			status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+				},
+			},
+			expected: "OOMKilled",
+		},
+		{
+			name: "terminated without reason",
+			status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2}}},
+				},
+			},
+			expected: "ExitCode:2",
+		},
+		{
+			name: "terminated by signal",
+			status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Signal: 9}}},
+				},
+			},
+			expected: "Signal:9",
+		},
+		{
+			name: "completed",
+			status: corev1.PodStatus{
+				Phase: corev1.PodSucceeded,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}},
+				},
+			},
+			expected: "Completed",
+		},
+		{
+			name: "completed next to running ready container",
+			status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}},
+					{Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				},
+			},
+			expected: "Running",
+		},
+		{
+			name: "completed next to running unready pod",
+			status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}},
+				},
+			},
+			expected: "NotReady",
+		},
+		{
+			name: "init progress",
+			spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "a"}, {Name: "b"}, {Name: "c"}}},
+			status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: "a", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}},
+					{Name: "b", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					{Name: "c", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+				},
+			},
+			expected: "Init:1/3",
+		},
+		{
+			name: "init crash loop",
+			spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "migrate"}}},
+			status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: "migrate", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				},
+			},
+			expected: "Init:CrashLoopBackOff",
+		},
+		{
+			name: "init failed with exit code",
+			spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "migrate"}}},
+			status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}},
+				},
+			},
+			expected: "Init:ExitCode:1",
+		},
+		{
+			name: "started sidecar does not block",
+			spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "proxy", RestartPolicy: &always}}},
+			status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: "proxy", Started: &started, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				},
+			},
+			expected: "Running",
+		},
+		{
+			name:     "evicted",
+			status:   corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"},
+			expected: "Evicted",
+		},
+		{
+			name:     "node lost",
+			status:   corev1.PodStatus{Phase: corev1.PodRunning, Reason: "NodeLost"},
+			expected: "NodeLost",
+		},
+		{
+			name:     "node lost while deleting",
+			status:   corev1.PodStatus{Phase: corev1.PodRunning, Reason: "NodeLost"},
+			deleted:  true,
+			expected: "Unknown",
+		},
+		{
+			name:     "terminating",
+			status:   corev1.PodStatus{Phase: corev1.PodRunning},
+			deleted:  true,
+			expected: "Terminating",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+				Spec:       tt.spec,
+				Status:     tt.status,
+			}
+			if tt.deleted {
+				pod.DeletionTimestamp = &now
+			}
+
+			_, err := clientset.CoreV1().Pods("default").Create(context.Background(), pod, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("Failed to create test pod: %v", err)
+			}
+
+			stored, err := clientset.CoreV1().Pods("default").Get(context.Background(), "test-pod", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Failed to get test pod: %v", err)
+			}
+
+			if result := getPodStatus(stored); result != tt.expected {
+				t.Errorf("getPodStatus() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetReadinessGates(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			ReadinessGates: []corev1.PodReadinessGate{
+				{ConditionType: "example.com/lb"},
+				{ConditionType: "example.com/dns"},
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: "example.com/lb", Status: corev1.ConditionTrue},
+				{Type: "example.com/dns", Status: corev1.ConditionFalse},
+			},
+		},
+	}
+
+	if result := getReadinessGates(pod); result != "1/2" {
+		t.Errorf("getReadinessGates() = %q, expected %q", result, "1/2")
+	}
+	if result := getReadinessGates(&corev1.Pod{}); result != NoneValue {
+		t.Errorf("getReadinessGates() without gates = %q, expected %q", result, NoneValue)
+	}
+}
+
+func TestGetNodeAddress(t *testing.T) {
+	node := &corev1.Node{
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "node-1"},
+				{Type: corev1.NodeInternalIP, Address: "192.168.1.10"},
+			},
+		},
+	}
+
+	if result := getNodeAddress(node, corev1.NodeInternalIP); result != "192.168.1.10" {
+		t.Errorf("getNodeAddress(InternalIP) = %q, expected %q", result, "192.168.1.10")
+	}
+	if result := getNodeAddress(node, corev1.NodeExternalIP); result != NoneValue {
+		t.Errorf("getNodeAddress(ExternalIP) = %q, expected %q", result, NoneValue)
+	}
+}
@@ -5,52 +5,226 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"regexp"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/robertusnegoro/k8ctl/internal/config"
 	"github.com/robertusnegoro/k8ctl/internal/errors"
 	"github.com/robertusnegoro/k8ctl/internal/k8s"
-	"github.com/robertusnegoro/k8ctl/internal/output"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
-var (
-	logLevelRegex = regexp.MustCompile(`(?i)(ERROR|WARN|WARNING|INFO|DEBUG|FATAL)`)
-)
+// selectorTailLines is the --tail used when it is not set and logs come from
+// many pods, as kubectl does for selectors.
+const selectorTailLines = 10
+
+// logsOptions holds the flags that select and shape log output.
+type logsOptions struct {
+	follow        bool
+	tailLines     int64
+	container     string
+	allContainers bool
+	selector      string
+	previous      bool
+	timestamps    bool
+	since         time.Duration
+	sinceTime     string
+	limitBytes    int64
+	raw           bool
+	outputFormat  string
+	grep          []string
+	exclude       []string
+	level         string
+	beforeContext int
+	afterContext  int
+	merge         bool
+	mergeWindow   time.Duration
+	stripTimes    bool
+	dumpDir       string
+	archive       bool
+	maxRequests   int
+
+	// fields names the fields read from structured log lines
+	fields config.LogsConfig
+	// sinceTimeValue is sinceTime parsed by complete
+	sinceTimeValue *metav1.Time
+	// filter is built from the filter flags by complete; nil prints every line
+	filter *logFilter
+	// out receives the logs instead of stdout
+	out io.Writer
+}
+
+func (o logsOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+// complete validates the flags and parses --since-time.
+func (o *logsOptions) complete() error {
+	if o.since != 0 && o.sinceTime != "" {
+		return fmt.Errorf("at most one of --since or --since-time may be specified")
+	}
+	if o.since < 0 {
+		return fmt.Errorf("--since must be a positive duration")
+	}
+	if o.limitBytes < 0 {
+		return fmt.Errorf("--limit-bytes must be greater than 0")
+	}
+	if o.allContainers && o.container != "" {
+		return fmt.Errorf("--all-containers cannot be combined with --container")
+	}
+	if o.outputFormat != "" && o.outputFormat != OutputFormatJSON {
+		return fmt.Errorf("unsupported output format '%s' for logs, only json is supported", o.outputFormat)
+	}
+	if o.raw && o.outputFormat != "" {
+		return fmt.Errorf("--raw cannot be combined with --output")
+	}
+	if o.dumpDir != "" && (o.follow || o.merge) {
+		return fmt.Errorf("--dump cannot be combined with --follow or --merge")
+	}
+	if o.archive && o.dumpDir == "" {
+		return fmt.Errorf("--archive only applies with --dump")
+	}
+	if o.dumpDir != "" && o.maxRequests < 1 {
+		return fmt.Errorf("--max-log-requests must be at least 1")
+	}
+	if o.stripTimes && !o.merge {
+		return fmt.Errorf("--strip-timestamps only applies with --merge")
+	}
+	if o.merge {
+		if o.mergeWindow <= 0 {
+			return fmt.Errorf("--merge-window must be a positive duration")
+		}
+		// Merging orders lines by the API timestamps, which are shown unless stripped
+		o.timestamps = !o.stripTimes
+	}
+	if o.sinceTime != "" {
+		sinceTime, err := time.Parse(time.RFC3339, o.sinceTime)
+		if err != nil {
+			return errors.WrapError(err, fmt.Sprintf("Invalid --since-time '%s', expected RFC3339 like 2024-01-02T15:04:05Z", o.sinceTime))
+		}
+		o.sinceTimeValue = &metav1.Time{Time: sinceTime}
+	}
+
+	filter, err := newLogFilter(*o)
+	if err != nil {
+		return err
+	}
+	o.filter = filter
+	return nil
+}
+
+// podLogOptions builds the log request for one container from the flags.
+func (o logsOptions) podLogOptions(container string) *corev1.PodLogOptions {
+	logOpts := &corev1.PodLogOptions{
+		Container:  container,
+		Follow:     o.follow,
+		Previous:   o.previous,
+		Timestamps: o.timestamps || o.merge,
+		SinceTime:  o.sinceTimeValue,
+	}
+	if o.tailLines >= 0 {
+		tailLines := o.tailLines
+		logOpts.TailLines = &tailLines
+	}
+	if o.since > 0 {
+		seconds := int64(o.since.Round(time.Second).Seconds())
+		logOpts.SinceSeconds = &seconds
+	}
+	if o.limitBytes > 0 {
+		limitBytes := o.limitBytes
+		logOpts.LimitBytes = &limitBytes
+	}
+	return logOpts
+}
 
 // NewLogsCommand creates a new logs command for displaying pod logs.
 func NewLogsCommand() *cobra.Command {
 	var namespace string
-	var follow bool
-	var tailLines int64
-	var container string
+	var opts logsOptions
 
 	cmd := &cobra.Command{
-		Use:   "logs [pod-name]",
-		Short: "Print the logs for a pod",
+		Use:   "logs [pod-name | pod-regex | type/name]",
+		Short: "Print the logs for one or many pods",
 		Long: `Print the logs for a pod with enhanced formatting including:
 - Log level color coding (ERROR, WARN, INFO, DEBUG)
-- Better timestamp formatting
-- Real-time streaming`,
-		Args: cobra.ExactArgs(1),
+- JSON and logfmt lines shown as aligned time, level, caller and message
+  columns, with the remaining fields dimmed
+- Real-time streaming
+
+The field names read from structured lines are set under logs: in the config
+file. --raw prints lines untouched and -o json re-emits normalized records.
+
+--grep, --exclude and --level filter lines as they stream, keeping the colors
+and highlighting what --grep matched; -A and -B add context lines as grep does.
+
+--merge orders the lines of all pods and containers by their API timestamps
+into one chronological stream, holding lines back for up to --merge-window
+while waiting for slower streams. Combine it with --since to backfill.
+
+--dump DIR saves the current and previous logs of every container of the
+selected pods, or of the whole namespace when nothing is selected, as
+DIR/namespace/pod/container[.previous].log with a manifest.json of what was
+captured; --archive also packs DIR into DIR.tar.gz.
+
+Logs from many pods can be streamed at once, each line prefixed with its
+pod and container: pass a workload (deploy/api, sts/db, svc/web), a label
+selector (-l app=api) or a regular expression matched against pod names.
+With --follow, pods that start or stop are picked up or dropped as they go.
+
+-c selects any container, including init and ephemeral containers, and
+--all-containers streams all of them.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runLogs(cmd, args, namespace, follow, tailLines, container)
+			return runLogs(cmd, args, namespace, opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (overrides config)")
-	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow log output")
-	cmd.Flags().Int64Var(&tailLines, "tail", 10, "Lines of recent log file to display")
-	cmd.Flags().StringVarP(&container, "container", "c", "", "Container name")
+	cmd.Flags().BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
+	cmd.Flags().Int64Var(&opts.tailLines, "tail", -1, "Lines of recent log file to display. Defaults to all lines for a single pod, otherwise 10")
+	cmd.Flags().StringVarP(&opts.container, "container", "c", "", "Container name, including init and ephemeral containers")
+	cmd.Flags().BoolVar(&opts.allContainers, "all-containers", false, "Get logs of all containers, including init and ephemeral containers")
+	cmd.Flags().StringVarP(&opts.selector, "selector", "l", "", "Stream logs from every pod matching this label selector")
+	cmd.Flags().BoolVarP(&opts.previous, "previous", "p", false, "Print the logs of the previous, terminated container instance")
+	cmd.Flags().BoolVar(&opts.timestamps, "timestamps", false, "Include timestamps on each line")
+	cmd.Flags().DurationVar(&opts.since, "since", 0, "Only return logs newer than a relative duration like 5s, 2m or 3h")
+	cmd.Flags().StringVar(&opts.sinceTime, "since-time", "", "Only return logs after a specific date (RFC3339)")
+	cmd.Flags().Int64Var(&opts.limitBytes, "limit-bytes", 0, "Maximum bytes of logs to return per container. Defaults to no limit")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Print log lines as they are, without parsing or colors")
+	cmd.Flags().StringVarP(&opts.outputFormat, "output", "o", "", "Output format: json re-emits each line as a normalized record")
+	cmd.Flags().StringArrayVar(&opts.grep, "grep", nil, "Only show lines matching this regular expression (repeatable, any may match)")
+	cmd.Flags().StringArrayVar(&opts.exclude, "exclude", nil, "Hide lines matching this regular expression (repeatable)")
+	cmd.Flags().StringVar(&opts.level, "level", "", "Only show lines at this level or above: trace, debug, info, warn, error or fatal")
+	cmd.Flags().IntVarP(&opts.afterContext, "after-context", "A", 0, "Lines of context to show after each matching line")
+	cmd.Flags().IntVarP(&opts.beforeContext, "before-context", "B", 0, "Lines of context to show before each matching line")
+	cmd.Flags().BoolVar(&opts.merge, "merge", false, "Merge the lines of all pods and containers in timestamp order")
+	cmd.Flags().DurationVar(&opts.mergeWindow, "merge-window", time.Second, "How long --merge waits for slower streams before printing a line")
+	cmd.Flags().BoolVar(&opts.stripTimes, "strip-timestamps", false, "Hide the timestamps --merge orders lines by")
+	cmd.Flags().StringVar(&opts.dumpDir, "dump", "", "Save the current and previous logs of every selected container under this directory")
+	cmd.Flags().BoolVar(&opts.archive, "archive", false, "Pack the --dump directory into a .tar.gz next to it")
+	cmd.Flags().IntVar(&opts.maxRequests, "max-log-requests", 5, "Maximum number of logs fetched at once by --dump")
 
 	return cmd
 }
 
-func runLogs(_ *cobra.Command, args []string, namespace string, follow bool, tailLines int64, container string) error {
-	podName := args[0]
+func runLogs(cmd *cobra.Command, args []string, namespace string, opts logsOptions) error {
+	if len(args) == 0 && opts.selector == "" && opts.dumpDir == "" {
+		return fmt.Errorf("a pod name, pod regex, type/name or --selector is required")
+	}
+	if err := opts.complete(); err != nil {
+		return err
+	}
+	opts.fields = config.Get().Logs
 
 	client, err := k8s.GetClient()
 	if err != nil {
@@ -65,68 +239,110 @@ func runLogs(_ *cobra.Command, args []string, namespace string, follow bool, tai
 		}
 	}
 
-	// Get pod to find container name if not specified
-	if container == "" {
-		pod, err := client.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
-		if err != nil {
-			return errors.HandleKubernetesError(err, "pod", podName, namespace)
-		}
-		if len(pod.Spec.Containers) > 0 {
-			container = pod.Spec.Containers[0].Name
+	// A plain pod name keeps the single-stream output without prefixes
+	if len(args) == 1 && opts.selector == "" && !strings.Contains(args[0], "/") {
+		pod, err := client.CoreV1().Pods(namespace).Get(context.Background(), args[0], metav1.GetOptions{})
+		switch {
+		case err == nil && opts.dumpDir != "":
+			return dumpLogs(client, namespace, podNameTarget(pod.Name), opts)
+		case err == nil && opts.allContainers:
+			return runLogTailer(client, namespace, podNameTarget(pod.Name), opts)
+		case err == nil:
+			return streamPodLogs(client, pod, opts)
+		case !apierrors.IsNotFound(err):
+			return errors.HandleKubernetesError(err, "pod", args[0], namespace)
 		}
 	}
 
-	opts := &corev1.PodLogOptions{
-		Container: container,
-		Follow:    follow,
-		TailLines: &tailLines,
+	target, err := resolveLogTarget(client, namespace, args, opts.selector)
+	if err != nil {
+		return err
+	}
+	if opts.dumpDir != "" {
+		return dumpLogs(client, namespace, target, opts)
 	}
+	if !target.single && !cmd.Flags().Changed("tail") {
+		opts.tailLines = selectorTailLines
+	}
+	return runLogTailer(client, namespace, target, opts)
+}
 
-	req := client.CoreV1().Pods(namespace).GetLogs(podName, opts)
-	stream, err := req.Stream(context.Background())
+// runLogTailer streams the target's logs until they end or the command is
+// interrupted.
+func runLogTailer(client kubernetes.Interface, namespace string, target logTarget, opts logsOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return newLogTailer(client, namespace, target, opts).run(ctx)
+}
+
+// streamPodLogs prints the logs of one container of a single pod.
+func streamPodLogs(client kubernetes.Interface, pod *corev1.Pod, opts logsOptions) error {
+	container := opts.container
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+	hint := crashLoopHint(pod, container, opts)
+
+	stream, err := openLogStream(context.Background(), client, pod.Namespace, pod.Name, opts.podLogOptions(container))
 	if err != nil {
+		if hint != "" {
+			return &errors.UserFriendlyError{
+				Message:    hint,
+				Original:   err,
+				Suggestion: fmt.Sprintf("k8ctl logs %s -c %s --previous -n %s", pod.Name, container, pod.Namespace),
+			}
+		}
 		return errors.WrapError(err, "Failed to retrieve pod logs")
 	}
 	defer func() {
 		_ = stream.Close()
 	}()
 
+	w := opts.writer()
+	formatter := newLogFormatter(opts)
+	printer := newLogPrinter(pod.Name, container, formatter, opts.filter)
+	lines := 0
 	scanner := bufio.NewScanner(stream)
 	for scanner.Scan() {
-		line := scanner.Text()
-		colorizeLogLine(line)
+		lines++
+		for _, line := range printer.lines(scanner.Text()) {
+			_, _ = fmt.Fprintln(w, line)
+		}
 	}
 
 	if err := scanner.Err(); err != nil && err != io.EOF {
 		return fmt.Errorf("error reading logs: %w", err)
 	}
+	if lines == 0 && hint != "" {
+		_, _ = fmt.Fprintln(w, formatter.notice(pod.Name, container, "WARN", hint))
+	}
 
 	return nil
 }
 
-func colorizeLogLine(line string) {
-	// Check for log levels and colorize
-	if logLevelRegex.MatchString(line) {
-		matches := logLevelRegex.FindStringSubmatch(line)
-		if len(matches) > 0 {
-			level := strings.ToUpper(matches[1])
-			switch level {
-			case "ERROR", "FATAL":
-				_, _ = output.LogError.Println(line)
-				return
-			case "WARN", "WARNING":
-				_, _ = output.LogWarn.Println(line)
-				return
-			case "INFO":
-				_, _ = output.LogInfo.Println(line)
-				return
-			case "DEBUG":
-				_, _ = output.LogDebug.Println(line)
-				return
-			}
-		}
+// openLogStream requests the logs of one container of a pod.
+func openLogStream(ctx context.Context, client kubernetes.Interface, namespace, pod string, logOpts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	return client.CoreV1().Pods(namespace).GetLogs(pod, logOpts).Stream(ctx)
+}
+
+// crashLoopHint explains an empty stream from a container in
+// CrashLoopBackOff, whose useful logs belong to the previous instance.
+func crashLoopHint(pod *corev1.Pod, container string, opts logsOptions) string {
+	if opts.previous {
+		return ""
 	}
+	status := containerStatus(pod, container)
+	if status == nil || status.State.Waiting == nil || status.State.Waiting.Reason != "CrashLoopBackOff" {
+		return ""
+	}
+	return fmt.Sprintf("container %s is in CrashLoopBackOff (restarted %d times); use --previous to see the logs of the crashed instance",
+		container, status.RestartCount)
+}
 
-	// Default output
-	fmt.Println(line)
+// colorizeLogLine colors an unstructured line by the level it starts with.
+func colorizeLogLine(line string) string {
+	if c := levelColor(plainLogLevel(line)); c != nil {
+		return c.Sprint(line)
+	}
+	return line
 }
@@ -0,0 +1,248 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// logDumpManifestName is the file listing what a dump captured
+	logDumpManifestName = "manifest.json"
+	// Logs may hold credentials or personal data, so dumps are private to
+	// their owner
+	logDumpDirMode  = 0o700
+	logDumpFileMode = 0o600
+)
+
+// logDumpManifest records what a dump captured and what failed.
+type logDumpManifest struct {
+	CapturedAt time.Time      `json:"capturedAt"`
+	Namespace  string         `json:"namespace"`
+	Selector   string         `json:"selector,omitempty"`
+	PodPattern string         `json:"podPattern,omitempty"`
+	Logs       []logDumpEntry `json:"logs"`
+}
+
+// logDumpEntry is one captured log file, or the error that prevented it.
+type logDumpEntry struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Previous  bool   `json:"previous,omitempty"`
+	File      string `json:"file,omitempty"`
+	Bytes     int64  `json:"bytes"`
+	Error     string `json:"error,omitempty"`
+}
+
+// dumpLogs saves the current and, after restarts, previous logs of every
+// container of the target pods under opts.dumpDir, fetching at most
+// opts.maxRequests logs at once.
+func dumpLogs(client kubernetes.Interface, namespace string, target logTarget, opts logsOptions) error {
+	ctx := context.Background()
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: target.selector.String()})
+	if err != nil {
+		return errors.HandleKubernetesError(err, "pods", "", namespace)
+	}
+
+	containerOpts := opts
+	containerOpts.allContainers = opts.container == ""
+	var entries []logDumpEntry
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !target.matches(pod) {
+			continue
+		}
+		for _, container := range selectContainers(pod, containerOpts) {
+			entries = append(entries, logDumpEntry{Pod: pod.Name, Container: container})
+			if status := containerStatus(pod, container); status != nil && status.LastTerminationState.Terminated != nil {
+				entries = append(entries, logDumpEntry{Pod: pod.Name, Container: container, Previous: true})
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no matching pods found in namespace %s", namespace)
+	}
+
+	var wg sync.WaitGroup
+	requests := make(chan struct{}, opts.maxRequests)
+	for i := range entries {
+		wg.Add(1)
+		go func(entry *logDumpEntry) {
+			defer wg.Done()
+			requests <- struct{}{}
+			defer func() { <-requests }()
+			dumpContainerLog(ctx, client, namespace, entry, opts)
+		}(&entries[i])
+	}
+	wg.Wait()
+
+	manifest := logDumpManifest{
+		CapturedAt: time.Now().UTC(),
+		Namespace:  namespace,
+		Logs:       entries,
+	}
+	if !target.selector.Empty() {
+		manifest.Selector = target.selector.String()
+	}
+	if target.podName != nil {
+		manifest.PodPattern = target.podName.String()
+	}
+	if err := writeLogDumpManifest(opts.dumpDir, manifest); err != nil {
+		return err
+	}
+
+	var failed int
+	var total int64
+	for _, entry := range entries {
+		if entry.Error != "" {
+			failed++
+		}
+		total += entry.Bytes
+	}
+
+	w := opts.writer()
+	location := opts.dumpDir
+	if opts.archive {
+		location = strings.TrimRight(opts.dumpDir, string(filepath.Separator)) + ".tar.gz"
+		if err := archiveDirectory(opts.dumpDir, location); err != nil {
+			return err
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%s %d logs (%d bytes) to %s\n",
+		output.Success.Sprint("Saved"), len(entries)-failed, total, location)
+	if failed > 0 {
+		_, _ = fmt.Fprintln(w, output.Warning.Sprintf("%d logs could not be fetched, see %s for the errors", failed, logDumpManifestName))
+		if failed == len(entries) {
+			return fmt.Errorf("no logs could be fetched")
+		}
+	}
+	return nil
+}
+
+// dumpContainerLog writes one container log to
+// dir/namespace/pod/container[.previous].log and records the outcome in entry.
+func dumpContainerLog(ctx context.Context, client kubernetes.Interface, namespace string, entry *logDumpEntry, opts logsOptions) {
+	name := entry.Container
+	if entry.Previous {
+		name += ".previous"
+	}
+	file := filepath.Join(namespace, entry.Pod, name+".log")
+
+	logOpts := opts.podLogOptions(entry.Container)
+	logOpts.Follow = false
+	logOpts.Previous = entry.Previous
+	stream, err := openLogStream(ctx, client, namespace, entry.Pod, logOpts)
+	if err != nil {
+		entry.Error = err.Error()
+		return
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	path := filepath.Join(opts.dumpDir, file)
+	if err := os.MkdirAll(filepath.Dir(path), logDumpDirMode); err != nil {
+		entry.Error = err.Error()
+		return
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, logDumpFileMode)
+	if err != nil {
+		entry.Error = err.Error()
+		return
+	}
+	entry.File = file
+	entry.Bytes, err = io.Copy(f, stream)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+}
+
+func writeLogDumpManifest(dir string, manifest logDumpManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode log manifest: %w", err)
+	}
+	if err := os.MkdirAll(dir, logDumpDirMode); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, logDumpManifestName), append(data, '\n'), logDumpFileMode); err != nil {
+		return fmt.Errorf("failed to write log manifest: %w", err)
+	}
+	return nil
+}
+
+// archiveDirectory packs dir into a gzipped tarball at path, with entries
+// rooted at the directory's name.
+func archiveDirectory(dir, path string) (err error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, logDumpFileMode)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write archive: %w", closeErr)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	root := filepath.Base(filepath.Clean(dir))
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(root, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		src, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = src.Close()
+		}()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", dir, err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
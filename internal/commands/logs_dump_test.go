@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDumpLogs(t *testing.T) {
+	restarted := testLogPod("api-0", map[string]string{"app": "api"}, "app")
+	restarted.Spec.InitContainers = []corev1.Container{{Name: "migrate"}}
+	restarted.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+	}
+	clientset := fake.NewSimpleClientset(
+		restarted,
+		testLogPod("db-0", map[string]string{"app": "db"}, "db"),
+	)
+
+	dir := filepath.Join(t.TempDir(), "incident")
+	var out strings.Builder
+	opts := logsOptions{tailLines: -1, dumpDir: dir, archive: true, maxRequests: 2, out: &out}
+	if err := opts.complete(); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	target := logTarget{selector: labels.SelectorFromSet(labels.Set{"app": "api"})}
+	if err := dumpLogs(clientset, "default", target, opts); err != nil {
+		t.Fatalf("dumpLogs failed: %v", err)
+	}
+
+	expectedFiles := []string{
+		"default/api-0/app.log",
+		"default/api-0/app.previous.log",
+		"default/api-0/migrate.log",
+	}
+	for _, file := range expectedFiles {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Errorf("Expected %s to be written: %v", file, err)
+			continue
+		}
+		if string(data) != "fake logs" {
+			t.Errorf("Unexpected content in %s: %q", file, data)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "default", "db-0")); !os.IsNotExist(err) {
+		t.Error("Pods outside the target should not be dumped")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, logDumpManifestName))
+	if err != nil {
+		t.Fatalf("Expected a manifest: %v", err)
+	}
+	var manifest logDumpManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Invalid manifest: %v", err)
+	}
+	var files []string
+	for _, entry := range manifest.Logs {
+		if entry.Error != "" || entry.Bytes != int64(len("fake logs")) {
+			t.Errorf("Unexpected manifest entry: %+v", entry)
+		}
+		files = append(files, filepath.ToSlash(entry.File))
+	}
+	sort.Strings(files)
+	if strings.Join(files, ",") != strings.Join(expectedFiles, ",") {
+		t.Errorf("Expected manifest files %v, got %v", expectedFiles, files)
+	}
+	if manifest.Namespace != "default" || manifest.Selector != "app=api" {
+		t.Errorf("Unexpected manifest header: %+v", manifest)
+	}
+	if !strings.Contains(out.String(), "3 logs") || !strings.Contains(out.String(), "incident.tar.gz") {
+		t.Errorf("Unexpected summary: %q", out.String())
+	}
+
+	names := readArchiveNames(t, dir+".tar.gz")
+	for _, file := range append(expectedFiles, logDumpManifestName) {
+		if !names["incident/"+file] {
+			t.Errorf("Expected incident/%s in the archive, got %v", file, names)
+		}
+	}
+
+	// Windows has no permission bits to check
+	if runtime.GOOS != "windows" {
+		modes := map[string]os.FileMode{
+			dir:                                     logDumpDirMode,
+			filepath.Join(dir, "default", "api-0"):  logDumpDirMode,
+			filepath.Join(dir, expectedFiles[0]):    logDumpFileMode,
+			filepath.Join(dir, logDumpManifestName): logDumpFileMode,
+			dir + ".tar.gz":                         logDumpFileMode,
+		}
+		for path, expected := range modes {
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("Failed to stat %s: %v", path, err)
+			}
+			if info.Mode().Perm() != expected {
+				t.Errorf("Expected %s to have mode %v, got %v", path, expected, info.Mode().Perm())
+			}
+		}
+	}
+}
+
+func TestDumpLogsOptions(t *testing.T) {
+	invalid := []logsOptions{
+		{dumpDir: "out", follow: true, maxRequests: 1},
+		{dumpDir: "out", merge: true, mergeWindow: 1, maxRequests: 1},
+		{dumpDir: "out"},
+		{archive: true},
+	}
+	for _, opts := range invalid {
+		if err := opts.complete(); err == nil {
+			t.Errorf("complete(%+v) should fail", opts)
+		}
+	}
+}
+
+func readArchiveNames(t *testing.T, path string) map[string]bool {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Expected an archive: %v", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("Invalid gzip archive: %v", err)
+	}
+	names := map[string]bool{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Invalid tar archive: %v", err)
+		}
+		names[header.Name] = true
+	}
+	return names
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+)
+
+// logLevelRanks orders the levels normalizeLogLevel produces, for --level
+var logLevelRanks = map[string]int{
+	"TRACE": 0,
+	"DEBUG": 1,
+	"INFO":  2,
+	"WARN":  3,
+	"ERROR": 4,
+	"FATAL": 5,
+}
+
+// logContextSeparator separates non-adjacent groups of context lines, as grep does
+const logContextSeparator = "--"
+
+// logFilter selects log lines by pattern and level.
+type logFilter struct {
+	include  []*regexp.Regexp
+	exclude  []*regexp.Regexp
+	minLevel string
+	before   int
+	after    int
+}
+
+// newLogFilter compiles the filter flags; it returns nil when no filter is set.
+func newLogFilter(opts logsOptions) (*logFilter, error) {
+	if opts.beforeContext < 0 || opts.afterContext < 0 {
+		return nil, fmt.Errorf("context line counts must not be negative")
+	}
+	if len(opts.grep) == 0 && len(opts.exclude) == 0 && opts.level == "" {
+		return nil, nil
+	}
+
+	filter := &logFilter{before: opts.beforeContext, after: opts.afterContext}
+	for _, pattern := range opts.grep {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.WrapError(err, fmt.Sprintf("Invalid --grep pattern '%s'", pattern))
+		}
+		filter.include = append(filter.include, re)
+	}
+	for _, pattern := range opts.exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.WrapError(err, fmt.Sprintf("Invalid --exclude pattern '%s'", pattern))
+		}
+		filter.exclude = append(filter.exclude, re)
+	}
+	if opts.level != "" {
+		filter.minLevel = normalizeLogLevel(opts.level)
+		if _, ok := logLevelRanks[filter.minLevel]; !ok {
+			return nil, fmt.Errorf("unknown log level '%s', expected one of trace, debug, info, warn, error or fatal", opts.level)
+		}
+	}
+	return filter, nil
+}
+
+// matches reports whether a line at level passes the filter. Lines must
+// match any --grep pattern and no --exclude pattern.
+func (f *logFilter) matches(line, level string) bool {
+	if f.minLevel != "" {
+		rank, ok := logLevelRanks[level]
+		if !ok || rank < logLevelRanks[f.minLevel] {
+			return false
+		}
+	}
+	for _, re := range f.exclude {
+		if re.MatchString(line) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlight marks the --grep matches in a rendered line.
+func (f *logFilter) highlight(line string) string {
+	for _, re := range f.include {
+		line = output.HighlightMatches(line, re, output.MatchHighlight)
+	}
+	return line
+}
+
+// logPrinter renders the lines of one container, applying the filter and
+// its context lines. It keeps per-stream state, so every stream needs its own.
+type logPrinter struct {
+	pod       string
+	container string
+	formatter *logFormatter
+	filter    *logFilter
+
+	// level is carried over to lines without one, like stack traces
+	level string
+	// pending holds the lines kept for --before-context
+	pending []string
+	// afterLeft counts the --after-context lines still to print
+	afterLeft int
+	printed   bool
+	// skipped is set when a line was dropped since the last one printed
+	skipped bool
+}
+
+func newLogPrinter(pod, container string, formatter *logFormatter, filter *logFilter) *logPrinter {
+	return &logPrinter{pod: pod, container: container, formatter: formatter, filter: filter}
+}
+
+// lines returns the rendered lines to print for a raw log line: none when it
+// is filtered out, or more when held context lines are released.
+func (p *logPrinter) lines(line string) []string {
+	if p.filter == nil {
+		return []string{p.formatter.format(p.pod, p.container, line)}
+	}
+
+	if level := p.formatter.level(line); level != "" {
+		p.level = level
+	}
+
+	if p.filter.matches(line, p.level) {
+		var rendered []string
+		if p.printed && p.skipped && (p.filter.before > 0 || p.filter.after > 0) && !p.formatter.json {
+			rendered = append(rendered, output.Dim.Sprint(logContextSeparator))
+		}
+		for _, held := range p.pending {
+			rendered = append(rendered, p.formatter.format(p.pod, p.container, held))
+		}
+		formatted := p.formatter.format(p.pod, p.container, line)
+		if !p.formatter.raw && !p.formatter.json {
+			formatted = p.filter.highlight(formatted)
+		}
+		rendered = append(rendered, formatted)
+
+		p.pending = p.pending[:0]
+		p.afterLeft = p.filter.after
+		p.printed = true
+		p.skipped = false
+		return rendered
+	}
+
+	if p.afterLeft > 0 {
+		p.afterLeft--
+		return []string{p.formatter.format(p.pod, p.container, line)}
+	}
+
+	if p.filter.before == 0 {
+		p.skipped = true
+		return nil
+	}
+	if len(p.pending) == p.filter.before {
+		p.pending = p.pending[1:]
+		p.skipped = true
+	}
+	p.pending = append(p.pending, line)
+	return nil
+}
@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/robertusnegoro/k8ctl/internal/output"
+)
+
+func TestNewLogFilter(t *testing.T) {
+	if filter, err := newLogFilter(logsOptions{}); err != nil || filter != nil {
+		t.Errorf("No filter expected without filter flags, got %v, %v", filter, err)
+	}
+
+	invalid := []logsOptions{
+		{grep: []string{"("}},
+		{exclude: []string{"["}},
+		{level: "loud"},
+		{grep: []string{"x"}, afterContext: -1},
+	}
+	for _, opts := range invalid {
+		if _, err := newLogFilter(opts); err == nil {
+			t.Errorf("newLogFilter(%+v) should fail", opts)
+		}
+	}
+}
+
+func TestLogFilterMatches(t *testing.T) {
+	filter, err := newLogFilter(logsOptions{
+		grep:    []string{"timeout", "refused"},
+		exclude: []string{"healthz"},
+		level:   "warning",
+	})
+	if err != nil {
+		t.Fatalf("newLogFilter failed: %v", err)
+	}
+
+	tests := []struct {
+		line     string
+		level    string
+		expected bool
+	}{
+		{line: "upstream timeout", level: "ERROR", expected: true},
+		{line: "connection refused", level: "WARN", expected: true},
+		{line: "upstream timeout", level: "INFO"},
+		{line: "upstream timeout", level: ""},
+		{line: "GET /healthz timeout", level: "ERROR"},
+		{line: "disk full", level: "FATAL"},
+	}
+	for _, tt := range tests {
+		if got := filter.matches(tt.line, tt.level); got != tt.expected {
+			t.Errorf("matches(%q, %q) = %t, expected %t", tt.line, tt.level, got, tt.expected)
+		}
+	}
+}
+
+func TestLogPrinterLevelIgnoresCase(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	filter, err := newLogFilter(logsOptions{level: "warn"})
+	if err != nil {
+		t.Fatalf("newLogFilter failed: %v", err)
+	}
+	printer := newLogPrinter("api-0", "app", newLogFormatter(logsOptions{raw: true}), filter)
+
+	var printed []string
+	for _, line := range []string{"info: starting", "error: disk full", "WARN slow request", "debug: tick"} {
+		printed = append(printed, printer.lines(line)...)
+	}
+	if got := strings.Join(printed, ","); got != "error: disk full,WARN slow request" {
+		t.Errorf("Expected the lowercase error line to pass --level, got %q", got)
+	}
+}
+
+func TestLogPrinterContext(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	filter, err := newLogFilter(logsOptions{grep: []string{"match"}, beforeContext: 1, afterContext: 1})
+	if err != nil {
+		t.Fatalf("newLogFilter failed: %v", err)
+	}
+	printer := newLogPrinter("api-0", "app", newLogFormatter(logsOptions{}), filter)
+
+	var printed []string
+	for _, line := range []string{"a", "b", "match 1", "c", "d", "e", "match 2", "match 3", "f", "g"} {
+		printed = append(printed, printer.lines(line)...)
+	}
+
+	expected := "b,match 1,c,--,e,match 2,match 3,f"
+	if got := strings.Join(printed, ","); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestLogPrinterLevelCarriesOver(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	filter, err := newLogFilter(logsOptions{level: "error"})
+	if err != nil {
+		t.Fatalf("newLogFilter failed: %v", err)
+	}
+	printer := newLogPrinter("api-0", "app", newLogFormatter(logsOptions{}), filter)
+
+	var printed []string
+	for _, line := range []string{
+		"INFO starting",
+		"ERROR request failed",
+		"    at handler (server.js:10)",
+		"INFO recovered",
+		"    at ignored (server.js:20)",
+	} {
+		printed = append(printed, printer.lines(line)...)
+	}
+
+	expected := "ERROR request failed|    at handler (server.js:10)"
+	if got := strings.Join(printed, "|"); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestLogPrinterHighlightsMatches(t *testing.T) {
+	wasEnabled := output.IsColorEnabled()
+	output.EnableColors()
+	defer func() {
+		if !wasEnabled {
+			output.DisableColors()
+		}
+	}()
+
+	filter, err := newLogFilter(logsOptions{grep: []string{"time.ut"}})
+	if err != nil {
+		t.Fatalf("newLogFilter failed: %v", err)
+	}
+	printer := newLogPrinter("api-0", "app", newLogFormatter(logsOptions{}), filter)
+	printed := printer.lines("upstream timeout")
+	if len(printed) != 1 || !strings.Contains(printed[0], output.MatchHighlight.Sprint("timeout")) {
+		t.Errorf("Expected the match to be highlighted, got %q", printed)
+	}
+
+	raw := newLogPrinter("api-0", "app", newLogFormatter(logsOptions{raw: true}), filter)
+	if printed := raw.lines("upstream timeout"); len(printed) != 1 || printed[0] != "upstream timeout" {
+		t.Errorf("--raw lines should not be highlighted, got %q", printed)
+	}
+}
@@ -0,0 +1,416 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/robertusnegoro/k8ctl/internal/config"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+)
+
+// logTimeLayout is how structured log timestamps are shown, so they line up
+const logTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+var (
+	// plainLevelRegex finds a level standing on its own near the start of an
+	// unstructured line, in any case, e.g. "2024-01-02 10:00:00 ERROR ..." or
+	// "[warn] ..."
+	plainLevelRegex = regexp.MustCompile(`(?i)^(?:\S+\s+){0,2}?\[?(TRACE|DEBUG|INFO|WARN|WARNING|ERROR|FATAL|PANIC)\]?(?:[\s:]|$)`)
+	// klogLevelRegex matches the header of klog lines, e.g. "E0102 15:04:05.000000"
+	klogLevelRegex = regexp.MustCompile(`^([IWEF])\d{4} \d{2}:\d{2}:\d{2}`)
+)
+
+// logRecord is a log line normalized from JSON, logfmt or plain text.
+type logRecord struct {
+	Time      string                 `json:"time,omitempty"`
+	Level     string                 `json:"level,omitempty"`
+	Message   string                 `json:"message"`
+	Caller    string                 `json:"caller,omitempty"`
+	Pod       string                 `json:"pod,omitempty"`
+	Container string                 `json:"container,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// logFormatter renders log lines: structured lines as aligned columns,
+// plain lines colored by level, or every line as a normalized JSON record.
+type logFormatter struct {
+	fields     config.LogsConfig
+	raw        bool
+	json       bool
+	timestamps bool
+
+	mu sync.Mutex
+	// callerWidth grows to the widest caller seen so messages stay aligned
+	callerWidth int
+}
+
+func newLogFormatter(opts logsOptions) *logFormatter {
+	return &logFormatter{
+		fields:     opts.fields.WithDefaults(),
+		raw:        opts.raw,
+		json:       opts.outputFormat == OutputFormatJSON,
+		timestamps: opts.timestamps,
+	}
+}
+
+// format renders one line logged by container of pod.
+func (f *logFormatter) format(pod, container, line string) string {
+	if f.raw {
+		return line
+	}
+
+	// --timestamps makes the kubelet prepend an RFC3339 timestamp
+	var kubeTime string
+	if f.timestamps {
+		if timestamp, rest, found := strings.Cut(line, " "); found {
+			kubeTime, line = timestamp, rest
+		}
+	}
+
+	record, structured := parseLogRecord(line, f.fields)
+	if record.Time == "" && kubeTime != "" {
+		record.Time = formatLogTime(kubeTime)
+	}
+
+	switch {
+	case f.json:
+		record.Pod, record.Container = pod, container
+		return marshalLogRecord(record)
+	case structured:
+		return f.render(record)
+	case kubeTime != "":
+		return output.Dim.Sprint(kubeTime) + " " + colorizeLogLine(line)
+	default:
+		return colorizeLogLine(line)
+	}
+}
+
+// level returns the normalized level of a line, or "" when it has none.
+func (f *logFormatter) level(line string) string {
+	if f.timestamps {
+		if _, rest, found := strings.Cut(line, " "); found {
+			line = rest
+		}
+	}
+	record, _ := parseLogRecord(line, f.fields)
+	return record.Level
+}
+
+// notice renders a message of k8ctl itself, such as a hint, like a log line.
+func (f *logFormatter) notice(pod, container, level, message string) string {
+	if f.json {
+		return marshalLogRecord(logRecord{Level: level, Message: message, Pod: pod, Container: container})
+	}
+	if c := levelColor(level); c != nil {
+		return c.Sprint(message)
+	}
+	return message
+}
+
+func (f *logFormatter) render(record logRecord) string {
+	parts := make([]string, 0, 5)
+	if record.Time != "" {
+		parts = append(parts, output.Dim.Sprint(record.Time))
+	}
+
+	level := fmt.Sprintf("%-5s", record.Level)
+	if c := levelColor(record.Level); c != nil {
+		level = c.Sprint(level)
+	}
+	parts = append(parts, level)
+
+	if record.Caller != "" {
+		f.mu.Lock()
+		if len(record.Caller) > f.callerWidth {
+			f.callerWidth = len(record.Caller)
+		}
+		width := f.callerWidth
+		f.mu.Unlock()
+		parts = append(parts, output.Dim.Sprintf("%-*s", width, record.Caller))
+	}
+
+	parts = append(parts, record.Message)
+	if len(record.Fields) > 0 {
+		parts = append(parts, output.Dim.Sprint(formatLogFields(record.Fields)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseLogRecord extracts the well-known fields of a JSON or logfmt line. It
+// reports false for unstructured lines, whose record holds the line as its
+// message.
+func parseLogRecord(line string, names config.LogsConfig) (logRecord, bool) {
+	fields, ok := parseJSONLine(line)
+	if !ok {
+		fields, ok = parseLogfmtLine(line)
+		if ok && !hasAnyField(fields, names.LevelFields) && !hasAnyField(fields, names.MessageFields) {
+			ok = false
+		}
+	}
+	if !ok {
+		return logRecord{Message: line, Level: plainLogLevel(line)}, false
+	}
+
+	record := logRecord{}
+	if value, found := takeLogField(fields, names.TimeFields); found {
+		record.Time = formatLogTime(value)
+	}
+	if value, found := takeLogField(fields, names.LevelFields); found {
+		record.Level = normalizeLogLevel(value)
+	}
+	if value, found := takeLogField(fields, names.MessageFields); found {
+		record.Message = logValueString(value)
+	}
+	if value, found := takeLogField(fields, names.CallerFields); found {
+		record.Caller = logValueString(value)
+	}
+	if len(fields) > 0 {
+		record.Fields = fields
+	}
+	return record, true
+}
+
+func parseJSONLine(line string) (map[string]interface{}, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil, false
+	}
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil || decoder.More() {
+		return nil, false
+	}
+	return fields, true
+}
+
+// parseLogfmtLine parses key=value pairs, with optionally quoted values. Any
+// token that is not a pair makes the line unstructured.
+func parseLogfmtLine(line string) (map[string]interface{}, bool) {
+	fields := map[string]interface{}{}
+	i := 0
+	for {
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		if i >= len(line) {
+			break
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		if i == start || i >= len(line) || line[i] != '=' {
+			return nil, false
+		}
+		key := line[start:i]
+		i++
+
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, false
+			}
+			value, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, false
+			}
+			fields[key] = value
+			i = end + 1
+			continue
+		}
+
+		start = i
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		fields[key] = line[start:i]
+	}
+	return fields, len(fields) > 0
+}
+
+func hasAnyField(fields map[string]interface{}, names []string) bool {
+	for _, name := range names {
+		if _, ok := fields[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// takeLogField removes and returns the first of names present in fields. A
+// dotted name also matches nested objects, e.g. "log.level" in ECS logs.
+func takeLogField(fields map[string]interface{}, names []string) (interface{}, bool) {
+	for _, name := range names {
+		if value, ok := fields[name]; ok {
+			delete(fields, name)
+			return value, true
+		}
+
+		path := strings.Split(name, ".")
+		if len(path) < 2 {
+			continue
+		}
+		parent := fields
+		for _, key := range path[:len(path)-1] {
+			nested, ok := parent[key].(map[string]interface{})
+			if !ok {
+				parent = nil
+				break
+			}
+			parent = nested
+		}
+		if value, ok := parent[path[len(path)-1]]; ok {
+			delete(parent, path[len(path)-1])
+			if len(parent) == 0 {
+				delete(fields, path[0])
+			}
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// normalizeLogLevel maps level names and bunyan/pino numeric levels onto
+// TRACE, DEBUG, INFO, WARN, ERROR and FATAL; unknown levels are upper-cased.
+func normalizeLogLevel(value interface{}) string {
+	level := strings.ToUpper(strings.TrimSpace(logValueString(value)))
+	if number, err := strconv.Atoi(level); err == nil {
+		switch {
+		case number <= 10:
+			return "TRACE"
+		case number <= 20:
+			return "DEBUG"
+		case number <= 30:
+			return "INFO"
+		case number <= 40:
+			return "WARN"
+		case number <= 50:
+			return "ERROR"
+		default:
+			return "FATAL"
+		}
+	}
+
+	switch level {
+	case "WARNING":
+		return "WARN"
+	case "ERR":
+		return "ERROR"
+	case "PANIC", "DPANIC", "CRITICAL", "CRIT", "ALERT", "EMERGENCY", "EMERG":
+		return "FATAL"
+	}
+	return level
+}
+
+// plainLogLevel finds the level of an unstructured line, or "" when the line
+// does not clearly carry one.
+func plainLogLevel(line string) string {
+	if matches := plainLevelRegex.FindStringSubmatch(line); matches != nil {
+		return normalizeLogLevel(matches[1])
+	}
+	if matches := klogLevelRegex.FindStringSubmatch(line); matches != nil {
+		return map[string]string{"I": "INFO", "W": "WARN", "E": "ERROR", "F": "FATAL"}[matches[1]]
+	}
+	return ""
+}
+
+func levelColor(level string) *color.Color {
+	switch level {
+	case "ERROR", "FATAL":
+		return output.LogError
+	case "WARN":
+		return output.LogWarn
+	case "INFO":
+		return output.LogInfo
+	case "DEBUG", "TRACE":
+		return output.LogDebug
+	}
+	return nil
+}
+
+// formatLogTime renders RFC3339 strings and Unix epochs (seconds or
+// milliseconds) in logTimeLayout; anything else is kept as logged.
+func formatLogTime(value interface{}) string {
+	text := logValueString(value)
+	if parsed, err := time.Parse(time.RFC3339Nano, text); err == nil {
+		return parsed.Format(logTimeLayout)
+	}
+	if epoch, err := strconv.ParseInt(text, 10, 64); err == nil {
+		if epoch > 1e12 {
+			return time.UnixMilli(epoch).UTC().Format(logTimeLayout)
+		}
+		return time.Unix(epoch, 0).UTC().Format(logTimeLayout)
+	}
+	epoch, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return text
+	}
+	if epoch > 1e12 {
+		epoch /= 1000
+	}
+	seconds := int64(epoch)
+	return time.Unix(seconds, int64((epoch-float64(seconds))*1e9)).UTC().Format(logTimeLayout)
+}
+
+func logValueString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// formatLogFields writes the remaining fields as sorted key=value pairs.
+func formatLogFields(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := logValueString(fields[key])
+		if value == "" || strings.ContainsAny(value, " \t") {
+			value = strconv.Quote(value)
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, " ")
+}
+
+func marshalLogRecord(record logRecord) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(record); err != nil {
+		return fmt.Sprintf(`{"message":%q}`, record.Message)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
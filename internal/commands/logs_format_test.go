@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/robertusnegoro/k8ctl/internal/config"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+)
+
+func TestParseLogRecord(t *testing.T) {
+	fields := config.DefaultLogsConfig()
+
+	tests := []struct {
+		name       string
+		line       string
+		structured bool
+		expected   logRecord
+		fields     string
+	}{
+		{
+			name:       "json",
+			line:       `{"level":"warning","ts":"2024-01-02T15:04:05.123456Z","msg":"slow request","caller":"api/handler.go:42","duration":1.5,"path":"/users"}`,
+			structured: true,
+			expected:   logRecord{Time: "2024-01-02T15:04:05.123Z", Level: "WARN", Message: "slow request", Caller: "api/handler.go:42"},
+			fields:     "duration=1.5 path=/users",
+		},
+		{
+			name:       "logfmt",
+			line:       `time=2024-01-02T15:04:05Z level=error msg="connection refused" retry=3`,
+			structured: true,
+			expected:   logRecord{Time: "2024-01-02T15:04:05.000Z", Level: "ERROR", Message: "connection refused"},
+			fields:     "retry=3",
+		},
+		{
+			name:       "nested ecs level and numeric epoch",
+			line:       `{"@timestamp":1704207845,"log":{"level":"info","logger":"db"},"message":"ready"}`,
+			structured: true,
+			expected:   logRecord{Time: "2024-01-02T15:04:05.000Z", Level: "INFO", Message: "ready"},
+			fields:     `log={"logger":"db"}`,
+		},
+		{
+			name:       "pino numeric level",
+			line:       `{"level":50,"time":1704207845123,"msg":"boom"}`,
+			structured: true,
+			expected:   logRecord{Time: "2024-01-02T15:04:05.123Z", Level: "ERROR", Message: "boom"},
+		},
+		{
+			name:     "plain line mentioning error",
+			line:     "Retrying after connection error",
+			expected: logRecord{Message: "Retrying after connection error"},
+		},
+		{
+			name:     "plain line with level",
+			line:     "2024-01-02 15:04:05 [WARN] disk almost full",
+			expected: logRecord{Level: "WARN", Message: "2024-01-02 15:04:05 [WARN] disk almost full"},
+		},
+		{
+			name:     "plain line with lowercase level",
+			line:     "2024-01-02 15:04:05 error: disk full",
+			expected: logRecord{Level: "ERROR", Message: "2024-01-02 15:04:05 error: disk full"},
+		},
+		{
+			name:     "plain line with bracketed lowercase level",
+			line:     "[info] listening on :8080",
+			expected: logRecord{Level: "INFO", Message: "[info] listening on :8080"},
+		},
+		{
+			name:     "klog line",
+			line:     "E0102 15:04:05.000000       1 controller.go:42] sync failed",
+			expected: logRecord{Level: "ERROR", Message: "E0102 15:04:05.000000       1 controller.go:42] sync failed"},
+		},
+		{
+			name:     "key value pairs without known fields",
+			line:     "a=b c=d",
+			expected: logRecord{Message: "a=b c=d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, structured := parseLogRecord(tt.line, fields)
+			if structured != tt.structured {
+				t.Errorf("Expected structured=%t, got %t", tt.structured, structured)
+			}
+			if record.Time != tt.expected.Time || record.Level != tt.expected.Level ||
+				record.Message != tt.expected.Message || record.Caller != tt.expected.Caller {
+				t.Errorf("Expected %+v, got %+v", tt.expected, record)
+			}
+			if got := formatLogFields(record.Fields); record.Fields != nil && got != tt.fields {
+				t.Errorf("Expected fields %q, got %q", tt.fields, got)
+			}
+		})
+	}
+}
+
+func TestParseLogRecordConfiguredFields(t *testing.T) {
+	fields := config.LogsConfig{LevelFields: []string{"sev"}, MessageFields: []string{"text"}}.WithDefaults()
+	record, structured := parseLogRecord(`sev=DEBUG text="cache miss" key=users`, fields)
+	if !structured || record.Level != "DEBUG" || record.Message != "cache miss" {
+		t.Errorf("Configured fields were not used: %+v", record)
+	}
+}
+
+func TestLogFormatter(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	formatter := newLogFormatter(logsOptions{})
+	first := formatter.format("api-0", "app", `{"level":"info","msg":"started","caller":"main.go:10","port":8080}`)
+	if first != "INFO  main.go:10 started port=8080" {
+		t.Errorf("Unexpected rendering: %q", first)
+	}
+	second := formatter.format("api-0", "app", `{"level":"error","msg":"failed","caller":"server/http.go:120"}`)
+	third := formatter.format("api-0", "app", `{"level":"info","msg":"done","caller":"main.go:12"}`)
+	if strings.Index(third, "done") != strings.Index(second, "failed") {
+		t.Errorf("Messages should stay aligned once a wider caller was seen:\n%s\n%s", second, third)
+	}
+	if plain := formatter.format("api-0", "app", "plain text"); plain != "plain text" {
+		t.Errorf("Plain lines should be kept, got %q", plain)
+	}
+
+	raw := newLogFormatter(logsOptions{raw: true})
+	line := `{"level":"info","msg":"started"}`
+	if got := raw.format("api-0", "app", line); got != line {
+		t.Errorf("--raw should print lines untouched, got %q", got)
+	}
+
+	timestamps := newLogFormatter(logsOptions{timestamps: true, outputFormat: OutputFormatJSON})
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(timestamps.format("api-0", "app", "2024-01-02T15:04:05.5Z hello <world>")), &record); err != nil {
+		t.Fatalf("Expected a JSON record: %v", err)
+	}
+	expected := map[string]interface{}{
+		"time": "2024-01-02T15:04:05.500Z", "message": "hello <world>", "pod": "api-0", "container": "app",
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, record[key])
+		}
+	}
+
+	if notice := timestamps.notice("api-0", "app", "WARN", "crashed"); !strings.Contains(notice, `"level":"WARN"`) {
+		t.Errorf("Notices should be JSON records too, got %q", notice)
+	}
+}
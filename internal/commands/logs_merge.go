@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"container/heap"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logEntry is a rendered line waiting in the merge buffer.
+type logEntry struct {
+	time    time.Time
+	seq     uint64
+	arrived time.Time
+	prefix  string
+	line    string
+}
+
+// logEntryHeap orders entries by timestamp, then by arrival.
+type logEntryHeap []*logEntry
+
+func (h logEntryHeap) Len() int { return len(h) }
+
+func (h logEntryHeap) Less(i, j int) bool {
+	if h[i].time.Equal(h[j].time) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].time.Before(h[j].time)
+}
+
+func (h logEntryHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *logEntryHeap) Push(x interface{}) { *h = append(*h, x.(*logEntry)) }
+
+func (h *logEntryHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// logMerger merges the streams of many containers into one chronological
+// stream. Each stream is already in order, so a line is released once every
+// open stream has reached its timestamp; a quiet stream holds lines back for
+// at most window.
+type logMerger struct {
+	window time.Duration
+	emit   func(prefix, line string)
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries logEntryHeap
+	// latest is the newest timestamp seen from each open stream
+	latest map[string]time.Time
+	seq    uint64
+}
+
+func newLogMerger(window time.Duration, emit func(prefix, line string)) *logMerger {
+	return &logMerger{
+		window: window,
+		emit:   emit,
+		now:    time.Now,
+		latest: map[string]time.Time{},
+	}
+}
+
+// open registers a stream whose lines must be waited for.
+func (m *logMerger) open(source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latest[source] = time.Time{}
+}
+
+// close unregisters a stream; its buffered lines are released in order.
+func (m *logMerger) close(source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.latest, source)
+	m.release(false)
+}
+
+// add buffers a line logged by source at timestamp.
+func (m *logMerger) add(source, prefix, line string, timestamp time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.latest[source]; ok && timestamp.After(m.latest[source]) {
+		m.latest[source] = timestamp
+	}
+	m.seq++
+	heap.Push(&m.entries, &logEntry{time: timestamp, seq: m.seq, arrived: m.now(), prefix: prefix, line: line})
+	m.release(false)
+}
+
+// run releases lines held back by quiet streams until ctx is cancelled.
+func (m *logMerger) run(ctx context.Context) {
+	ticker := time.NewTicker(m.window / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			m.release(false)
+			m.mu.Unlock()
+		}
+	}
+}
+
+// flush releases every buffered line.
+func (m *logMerger) flush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.release(true)
+}
+
+// release emits the lines that can no longer be preceded by another line.
+// The caller holds mu, which also keeps emitted lines in order.
+func (m *logMerger) release(all bool) {
+	watermark, waiting := m.watermark()
+	now := m.now()
+	for m.entries.Len() > 0 {
+		entry := m.entries[0]
+		ready := all || !waiting || !entry.time.After(watermark) || now.Sub(entry.arrived) >= m.window
+		if !ready {
+			return
+		}
+		heap.Pop(&m.entries)
+		m.emit(entry.prefix, entry.line)
+	}
+}
+
+// watermark is the oldest of the newest timestamps of the open streams;
+// waiting is false when no stream is open.
+func (m *logMerger) watermark() (time.Time, bool) {
+	var watermark time.Time
+	first := true
+	for _, latest := range m.latest {
+		if first || latest.Before(watermark) {
+			watermark = latest
+			first = false
+		}
+	}
+	return watermark, !first
+}
+
+// splitLogTimestamp separates the timestamp the API prepends with
+// Timestamps set from the line.
+func splitLogTimestamp(line string) (time.Time, string, bool) {
+	timestamp, rest, found := strings.Cut(line, " ")
+	if !found {
+		timestamp, rest = line, ""
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return time.Time{}, line, false
+	}
+	return parsed, rest, true
+}
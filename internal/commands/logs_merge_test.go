@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLogMergerOrdersStreams(t *testing.T) {
+	base := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	now := base
+	var emitted []string
+	merger := newLogMerger(time.Second, func(prefix, line string) {
+		emitted = append(emitted, prefix+" "+line)
+	})
+	merger.now = func() time.Time { return now }
+
+	merger.open("a")
+	merger.open("b")
+
+	merger.add("a", "[a]", "a1", base.Add(1*time.Millisecond))
+	merger.add("a", "[a]", "a3", base.Add(3*time.Millisecond))
+	if len(emitted) != 0 {
+		t.Fatalf("Nothing should be released before every stream reported, got %v", emitted)
+	}
+
+	merger.add("b", "[b]", "b2", base.Add(2*time.Millisecond))
+	if got := strings.Join(emitted, ","); got != "[a] a1,[b] b2" {
+		t.Errorf("Expected lines up to the slowest stream, got %q", got)
+	}
+
+	// b stays quiet: a3 is released once it waited for the whole window,
+	// while the newer a5 is still held
+	now = now.Add(time.Second)
+	merger.add("a", "[a]", "a5", base.Add(5*time.Millisecond))
+	if got := strings.Join(emitted, ","); got != "[a] a1,[b] b2,[a] a3" {
+		t.Errorf("Expected held lines to be released after the window, got %q", got)
+	}
+
+	merger.add("b", "[b]", "b4", base.Add(4*time.Millisecond))
+	merger.add("a", "[a]", "a6", base.Add(6*time.Millisecond))
+	merger.close("b")
+	merger.flush()
+	if got := strings.Join(emitted[3:], ","); got != "[b] b4,[a] a5,[a] a6" {
+		t.Errorf("Expected the remaining lines in order, got %q", got)
+	}
+}
+
+func TestSplitLogTimestamp(t *testing.T) {
+	timestamp, rest, ok := splitLogTimestamp("2024-01-02T15:04:05.123456789Z GET /users 200")
+	if !ok || rest != "GET /users 200" || timestamp.Nanosecond() != 123456789 {
+		t.Errorf("Unexpected split: %v %q %t", timestamp, rest, ok)
+	}
+	if _, rest, ok := splitLogTimestamp("no timestamp here"); ok || rest != "no timestamp here" {
+		t.Errorf("Lines without a timestamp should be kept, got %q %t", rest, ok)
+	}
+}
+
+func TestLogTailerMerge(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		testLogPod("api-0", nil, "app"),
+		testLogPod("api-1", nil, "app"),
+	)
+
+	opts := logsOptions{merge: true, mergeWindow: time.Second, stripTimes: true}
+	if err := opts.complete(); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	if logOpts := opts.podLogOptions("app"); !logOpts.Timestamps {
+		t.Error("--merge should request timestamps from the API")
+	}
+
+	var out syncBuffer
+	opts.out = &out
+	tailer := newLogTailer(clientset, "default", logTarget{selector: labels.Everything()}, opts)
+	if err := tailer.run(context.Background()); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if result := out.String(); strings.Count(result, "fake logs") != 2 {
+		t.Errorf("Expected both pods in the merged output, got:\n%s", result)
+	}
+
+	invalid := []logsOptions{
+		{stripTimes: true},
+		{merge: true},
+	}
+	for _, opts := range invalid {
+		if err := opts.complete(); err == nil {
+			t.Errorf("complete(%+v) should fail", opts)
+		}
+	}
+}
@@ -0,0 +1,558 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// logWatchRetryDelay is the first wait before a lost pod watch is resumed
+	logWatchRetryDelay = time.Second
+	// logWatchMaxRetryDelay caps the doubling wait between attempts
+	logWatchMaxRetryDelay = 30 * time.Second
+)
+
+// logTarget selects the pods whose logs are streamed.
+type logTarget struct {
+	selector labels.Selector
+	// podName, when set, must match the pod name
+	podName *regexp.Regexp
+	// single is set when the target names exactly one pod
+	single bool
+}
+
+// podNameTarget selects the pod with exactly this name.
+func podNameTarget(name string) logTarget {
+	return logTarget{
+		selector: labels.Everything(),
+		podName:  regexp.MustCompile("^" + regexp.QuoteMeta(name) + "$"),
+		single:   true,
+	}
+}
+
+func (t logTarget) matches(pod *corev1.Pod) bool {
+	if !t.selector.Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+	return t.podName == nil || t.podName.MatchString(pod.Name)
+}
+
+// resolveLogTarget turns the logs arguments into a pod selection: a
+// workload or pod in type/name form, a regular expression over pod names,
+// and/or a label selector.
+func resolveLogTarget(client kubernetes.Interface, namespace string, args []string, selector string) (logTarget, error) {
+	target := logTarget{selector: labels.Everything()}
+	if selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return target, errors.WrapError(err, fmt.Sprintf("Invalid label selector '%s'", selector))
+		}
+		target.selector = parsed
+	}
+	if len(args) == 0 {
+		return target, nil
+	}
+
+	arg := args[0]
+	resourceType, name, found := strings.Cut(arg, "/")
+	if !found {
+		podName, err := regexp.Compile(arg)
+		if err != nil {
+			return target, errors.WrapError(err, fmt.Sprintf("Invalid pod name pattern '%s'", arg))
+		}
+		target.podName = podName
+		return target, nil
+	}
+
+	resourceType = expandResourceType(strings.ToLower(resourceType))
+	if resourceType == ResourcePods || resourceType == ResourcePod {
+		podTarget := podNameTarget(name)
+		target.podName = podTarget.podName
+		target.single = selector == ""
+		return target, nil
+	}
+
+	workloadSelector, err := podSelectorOf(client, namespace, resourceType, name)
+	if err != nil {
+		return target, err
+	}
+	requirements, _ := workloadSelector.Requirements()
+	target.selector = target.selector.Add(requirements...)
+	return target, nil
+}
+
+// podSelectorOf returns the label selector a workload or service uses to
+// pick its pods.
+func podSelectorOf(client kubernetes.Interface, namespace, resourceType, name string) (labels.Selector, error) {
+	ctx := context.Background()
+	var selector *metav1.LabelSelector
+	var err error
+
+	switch resourceType {
+	case ResourceDeployments, ResourceDeployment:
+		deployment, getErr := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector = deployment.Spec.Selector
+		}
+	case ResourceStatefulSets, ResourceStatefulSet:
+		statefulSet, getErr := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector = statefulSet.Spec.Selector
+		}
+	case ResourceDaemonSets, ResourceDaemonSet:
+		daemonSet, getErr := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector = daemonSet.Spec.Selector
+		}
+	case ResourceReplicaSets, ResourceReplicaSet:
+		replicaSet, getErr := client.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector = replicaSet.Spec.Selector
+		}
+	case ResourceJobs, ResourceJob:
+		job, getErr := client.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector = job.Spec.Selector
+		}
+	case ResourceServices, ResourceService:
+		service, getErr := client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil {
+			return nil, errors.HandleKubernetesError(getErr, "service", name, namespace)
+		}
+		if len(service.Spec.Selector) == 0 {
+			return nil, fmt.Errorf("service %s has no selector, so its pods cannot be found", name)
+		}
+		return labels.SelectorFromSet(service.Spec.Selector), nil
+	default:
+		return nil, errors.WrapError(
+			fmt.Errorf("resource type %s has no pods", resourceType),
+			fmt.Sprintf("Logs can be streamed for pods, deployments, statefulsets, daemonsets, replicasets, jobs and services, not '%s'", resourceType),
+		)
+	}
+
+	if err != nil {
+		return nil, errors.HandleKubernetesError(err, strings.TrimSuffix(resourceType, "s"), name, namespace)
+	}
+	if selector == nil {
+		return nil, fmt.Errorf("%s %s has no selector", strings.TrimSuffix(resourceType, "s"), name)
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// logTailer streams the logs of every container of the matching pods
+// concurrently, prefixing each line with its source.
+type logTailer struct {
+	client    kubernetes.Interface
+	namespace string
+	target    logTarget
+	opts      logsOptions
+	formatter *logFormatter
+	// merger orders lines across streams with --merge
+	merger *logMerger
+	// retryDelay is the first wait before resuming a lost pod watch
+	retryDelay time.Duration
+
+	mu sync.Mutex
+	// active cancels the running stream of each pod/container
+	active map[string]context.CancelFunc
+	// ended records when a stream stopped, so a restarted container resumes
+	// from there instead of repeating its tail
+	ended map[string]time.Time
+	wg    sync.WaitGroup
+
+	// outMu keeps lines from different streams from interleaving
+	outMu sync.Mutex
+}
+
+func newLogTailer(client kubernetes.Interface, namespace string, target logTarget, opts logsOptions) *logTailer {
+	t := &logTailer{
+		client:     client,
+		namespace:  namespace,
+		target:     target,
+		opts:       opts,
+		formatter:  newLogFormatter(opts),
+		retryDelay: logWatchRetryDelay,
+		active:     map[string]context.CancelFunc{},
+		ended:      map[string]time.Time{},
+	}
+	if opts.merge {
+		t.merger = newLogMerger(opts.mergeWindow, t.writeLine)
+	}
+	return t
+}
+
+// run streams the pods that match now and, with --follow, keeps watching
+// for pods that start or go away until ctx is cancelled.
+func (t *logTailer) run(ctx context.Context) error {
+	// Deferred calls run last first: when following, streams are cancelled,
+	// then waited for
+	defer t.finish()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if t.merger != nil {
+		go t.merger.run(ctx)
+	}
+
+	pods := t.client.CoreV1().Pods(t.namespace)
+	listOpts := metav1.ListOptions{LabelSelector: t.target.selector.String()}
+	list, err := pods.List(ctx, listOpts)
+	if err != nil {
+		return errors.HandleKubernetesError(err, "pods", "", t.namespace)
+	}
+
+	matched := 0
+	for i := range list.Items {
+		if t.target.matches(&list.Items[i]) {
+			t.start(ctx, &list.Items[i])
+			matched++
+		}
+	}
+
+	if !t.opts.follow {
+		if matched == 0 {
+			return fmt.Errorf("no matching pods found in namespace %s", t.namespace)
+		}
+		// Without --follow the streams end on their own; let them finish
+		t.wg.Wait()
+		return nil
+	}
+
+	listOpts.ResourceVersion = list.ResourceVersion
+	t.follow(ctx, listOpts)
+	return nil
+}
+
+// follow watches the matching pods until ctx is cancelled, starting and
+// stopping streams as pods come and go. A lost watch is resumed from the last
+// seen resourceVersion after a growing delay; once that version has expired
+// (410 Gone) the pods are listed afresh.
+func (t *logTailer) follow(ctx context.Context, listOpts metav1.ListOptions) {
+	pods := t.client.CoreV1().Pods(t.namespace)
+	listOpts.AllowWatchBookmarks = true
+	delay := t.retryDelay
+	relist, lost := false, false
+	for {
+		var err error
+		if relist {
+			listOpts.ResourceVersion, err = t.relist(ctx, listOpts)
+			relist = err != nil
+		}
+		if err == nil {
+			var watcher watch.Interface
+			watcher, err = pods.Watch(ctx, listOpts)
+			if err == nil {
+				if lost {
+					t.status("INFO", fmt.Sprintf("watch resumed at resourceVersion %s", listOpts.ResourceVersion))
+					lost = false
+				}
+				var received bool
+				listOpts.ResourceVersion, received, err = t.watchPods(ctx, watcher, listOpts.ResourceVersion)
+				if received {
+					delay = t.retryDelay
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch {
+		case isExpiredWatchError(err):
+			t.status("WARN", fmt.Sprintf("resourceVersion %s expired (410 Gone), relisting pods in %s", listOpts.ResourceVersion, delay))
+			listOpts.ResourceVersion = ""
+			relist = true
+		case err == nil:
+			t.status("WARN", fmt.Sprintf("watch closed, resuming in %s", delay))
+		default:
+			t.status("WARN", fmt.Sprintf("watch connection lost (%v), retrying in %s", err, delay))
+		}
+		lost = true
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, logWatchMaxRetryDelay)
+	}
+}
+
+// watchPods applies the events of watcher until it ends, returning the last
+// seen resourceVersion, whether any event arrived and the error the watch
+// ended with, if any.
+func (t *logTailer) watchPods(ctx context.Context, watcher watch.Interface, resourceVersion string) (string, bool, error) {
+	defer watcher.Stop()
+	received := false
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, received, nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, received, nil
+			}
+			if event.Type == watch.Error {
+				return resourceVersion, received, apierrors.FromObject(event.Object)
+			}
+			received = true
+			pod, ok := event.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			if pod.ResourceVersion != "" {
+				resourceVersion = pod.ResourceVersion
+			}
+			if event.Type == watch.Bookmark || !t.target.matches(pod) {
+				continue
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				t.start(ctx, pod)
+			case watch.Deleted:
+				t.stop(pod)
+			}
+		}
+	}
+}
+
+// relist lists the matching pods again after the watch fell too far behind,
+// starting new pods and stopping the streams of pods that went away. It
+// returns the resourceVersion to watch from.
+func (t *logTailer) relist(ctx context.Context, listOpts metav1.ListOptions) (string, error) {
+	list, err := t.client.CoreV1().Pods(t.namespace).List(ctx, listOpts)
+	if err != nil {
+		return "", err
+	}
+	present := map[string]bool{}
+	for i := range list.Items {
+		if t.target.matches(&list.Items[i]) {
+			present[list.Items[i].Name] = true
+			t.start(ctx, &list.Items[i])
+		}
+	}
+
+	gone := map[string]bool{}
+	t.mu.Lock()
+	for key := range t.active {
+		if name, _, _ := strings.Cut(key, "/"); !present[name] {
+			gone[name] = true
+		}
+	}
+	for key := range t.ended {
+		if name, _, _ := strings.Cut(key, "/"); !present[name] {
+			gone[name] = true
+		}
+	}
+	t.mu.Unlock()
+	for name := range gone {
+		t.stop(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	return list.ResourceVersion, nil
+}
+
+// status prints the state of the pod watch between the log lines.
+func (t *logTailer) status(level, message string) {
+	t.writeLine(output.Dim.Sprint("[watch]"), t.formatter.notice("", "", level, message))
+}
+
+// finish waits for the streams and prints what the merger still holds.
+func (t *logTailer) finish() {
+	t.wg.Wait()
+	if t.merger != nil {
+		t.merger.flush()
+	}
+}
+
+// start streams each selected container of pod that is not streamed yet.
+// When following, containers that have not started are left for a later
+// pod event.
+func (t *logTailer) start(ctx context.Context, pod *corev1.Pod) {
+	for _, container := range selectContainers(pod, t.opts) {
+		if t.opts.follow && !t.opts.previous && !containerStarted(pod, container) {
+			continue
+		}
+
+		key := pod.Name + "/" + container
+		t.mu.Lock()
+		if _, ok := t.active[key]; ok {
+			t.mu.Unlock()
+			continue
+		}
+		streamCtx, cancel := context.WithCancel(ctx)
+		t.active[key] = cancel
+		logOpts := t.opts.podLogOptions(container)
+		if ended, ok := t.ended[key]; ok {
+			logOpts.SinceTime = &metav1.Time{Time: ended}
+			logOpts.SinceSeconds = nil
+			logOpts.TailLines = nil
+		}
+		t.mu.Unlock()
+
+		if t.merger != nil {
+			t.merger.open(key)
+		}
+		t.wg.Add(1)
+		go t.stream(streamCtx, pod.Name, container, logOpts, crashLoopHint(pod, container, t.opts))
+	}
+}
+
+// stop cancels the streams of a deleted pod.
+func (t *logTailer) stop(pod *corev1.Pod) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, cancel := range t.active {
+		if strings.HasPrefix(key, pod.Name+"/") {
+			cancel()
+		}
+	}
+	for key := range t.ended {
+		if strings.HasPrefix(key, pod.Name+"/") {
+			delete(t.ended, key)
+		}
+	}
+}
+
+// stream copies one container's logs to the output; hint is printed when the
+// stream turns out empty.
+func (t *logTailer) stream(ctx context.Context, podName, container string, logOpts *corev1.PodLogOptions, hint string) {
+	key := podName + "/" + container
+	defer t.wg.Done()
+	defer func() {
+		t.mu.Lock()
+		if ctx.Err() == nil {
+			t.ended[key] = time.Now()
+		}
+		t.active[key]()
+		delete(t.active, key)
+		t.mu.Unlock()
+		if t.merger != nil {
+			t.merger.close(key)
+		}
+	}()
+
+	prefix := output.PrefixColor(podName).Sprintf("[%s]", key)
+	stream, err := openLogStream(ctx, t.client, t.namespace, podName, logOpts)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		if hint != "" {
+			t.writeLine(prefix, t.formatter.notice(podName, container, "WARN", hint))
+			return
+		}
+		t.writeLine(prefix, t.formatter.notice(podName, container, "ERROR", fmt.Sprintf("failed to stream logs: %v", err)))
+		return
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	printer := newLogPrinter(podName, container, t.formatter, t.opts.filter)
+	var timestamp time.Time
+	lines := 0
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		lines++
+		line := scanner.Text()
+		if t.merger == nil {
+			for _, rendered := range printer.lines(line) {
+				t.writeLine(prefix, rendered)
+			}
+			continue
+		}
+
+		// Lines without a timestamp keep the previous one of their stream
+		if parsed, rest, ok := splitLogTimestamp(line); ok {
+			timestamp = parsed
+			if t.opts.stripTimes {
+				line = rest
+			}
+		} else if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+		for _, rendered := range printer.lines(line) {
+			t.merger.add(key, prefix, rendered, timestamp)
+		}
+	}
+	if lines == 0 && hint != "" && ctx.Err() == nil {
+		t.writeLine(prefix, t.formatter.notice(podName, container, "WARN", hint))
+	}
+}
+
+// writeLine prints a rendered line after its source prefix; JSON records
+// carry their source instead.
+func (t *logTailer) writeLine(prefix, line string) {
+	t.outMu.Lock()
+	defer t.outMu.Unlock()
+	if t.formatter.json {
+		_, _ = fmt.Fprintln(t.opts.writer(), line)
+		return
+	}
+	_, _ = fmt.Fprintln(t.opts.writer(), prefix, line)
+}
+
+// selectContainers returns the containers of pod to stream: the one named by
+// -c, which may be an init or ephemeral container, every container with
+// --all-containers, or otherwise the regular containers.
+func selectContainers(pod *corev1.Pod, opts logsOptions) []string {
+	var names []string
+	if opts.container != "" || opts.allContainers {
+		for _, container := range pod.Spec.InitContainers {
+			names = append(names, container.Name)
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	if opts.container != "" || opts.allContainers {
+		for _, container := range pod.Spec.EphemeralContainers {
+			names = append(names, container.Name)
+		}
+	}
+
+	if opts.container == "" {
+		return names
+	}
+	for _, name := range names {
+		if name == opts.container {
+			return []string{name}
+		}
+	}
+	return nil
+}
+
+// containerStatus finds the status of a regular, init or ephemeral container.
+func containerStatus(pod *corev1.Pod, name string) *corev1.ContainerStatus {
+	for _, list := range [][]corev1.ContainerStatus{
+		pod.Status.ContainerStatuses,
+		pod.Status.InitContainerStatuses,
+		pod.Status.EphemeralContainerStatuses,
+	} {
+		for i := range list {
+			if list[i].Name == name {
+				return &list[i]
+			}
+		}
+	}
+	return nil
+}
+
+// containerStarted reports whether a container has logs to stream yet.
+func containerStarted(pod *corev1.Pod, name string) bool {
+	status := containerStatus(pod, name)
+	return status != nil && (status.State.Running != nil || status.State.Terminated != nil)
+}
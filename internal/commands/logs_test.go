@@ -0,0 +1,344 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// syncBuffer is a bytes.Buffer that can be written by concurrent streams
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func testLogPod(name string, podLabels map[string]string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: podLabels}}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:  container,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+func TestResolveLogTarget(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+		},
+	)
+
+	api := testLogPod("api-7d9f-abcde", map[string]string{"app": "api"}, "app")
+	web := testLogPod("web-0", map[string]string{"app": "web"}, "app")
+
+	tests := []struct {
+		name     string
+		args     []string
+		selector string
+		matches  []*corev1.Pod
+		excludes []*corev1.Pod
+	}{
+		{name: "deployment", args: []string{"deploy/api"}, matches: []*corev1.Pod{api}, excludes: []*corev1.Pod{web}},
+		{name: "service", args: []string{"svc/web"}, matches: []*corev1.Pod{web}, excludes: []*corev1.Pod{api}},
+		{name: "pod", args: []string{"pod/web-0"}, matches: []*corev1.Pod{web}, excludes: []*corev1.Pod{api}},
+		{name: "regex", args: []string{"^api-"}, matches: []*corev1.Pod{api}, excludes: []*corev1.Pod{web}},
+		{name: "selector", selector: "app=web", matches: []*corev1.Pod{web}, excludes: []*corev1.Pod{api}},
+		{name: "selector and deployment", args: []string{"deploy/api"}, selector: "app=web", excludes: []*corev1.Pod{api, web}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := resolveLogTarget(clientset, "default", tt.args, tt.selector)
+			if err != nil {
+				t.Fatalf("resolveLogTarget failed: %v", err)
+			}
+			for _, pod := range tt.matches {
+				if !target.matches(pod) {
+					t.Errorf("Expected %s to match", pod.Name)
+				}
+			}
+			for _, pod := range tt.excludes {
+				if target.matches(pod) {
+					t.Errorf("Expected %s not to match", pod.Name)
+				}
+			}
+		})
+	}
+
+	for _, args := range [][]string{{"deploy/missing"}, {"cm/config"}, {"api-("}} {
+		if _, err := resolveLogTarget(clientset, "default", args, ""); err == nil {
+			t.Errorf("resolveLogTarget(%v) should fail", args)
+		}
+	}
+}
+
+func TestLogTailerStreamsAllPodsAndContainers(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		testLogPod("api-0", map[string]string{"app": "api"}, "app", "sidecar"),
+		testLogPod("api-1", map[string]string{"app": "api"}, "app"),
+		testLogPod("db-0", map[string]string{"app": "db"}, "db"),
+	)
+
+	var out syncBuffer
+	target := logTarget{selector: labels.SelectorFromSet(labels.Set{"app": "api"})}
+	tailer := newLogTailer(clientset, "default", target, logsOptions{tailLines: 10, out: &out})
+	if err := tailer.run(context.Background()); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	result := out.String()
+	for _, expected := range []string{"[api-0/app] fake logs", "[api-0/sidecar] fake logs", "[api-1/app] fake logs"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(result, "db-0") {
+		t.Errorf("Pods outside the selector should not be streamed, got:\n%s", result)
+	}
+
+	tailer = newLogTailer(clientset, "default", logTarget{selector: labels.Everything()}, logsOptions{container: "sidecar", out: &out})
+	out.buf.Reset()
+	if err := tailer.run(context.Background()); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if result := out.String(); strings.Count(result, "fake logs") != 1 || !strings.Contains(result, "[api-0/sidecar]") {
+		t.Errorf("Expected only the sidecar container, got:\n%s", result)
+	}
+
+	empty := newLogTailer(clientset, "default", logTarget{selector: labels.SelectorFromSet(labels.Set{"app": "none"})}, logsOptions{out: &out})
+	if err := empty.run(context.Background()); err == nil {
+		t.Error("run should fail when no pods match")
+	}
+}
+
+func TestLogTailerFollowPicksUpNewPods(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	clientset.PrependWatchReactor("pods", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	var out syncBuffer
+	tailer := newLogTailer(clientset, "default", logTarget{selector: labels.Everything()}, logsOptions{follow: true, out: &out})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- tailer.run(ctx) }()
+
+	pending := testLogPod("api-0", nil, "app")
+	pending.Status.ContainerStatuses = nil
+	watcher.Add(pending)
+	watcher.Modify(testLogPod("api-0", nil, "app"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "[api-0/app] fake logs") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	if result := out.String(); strings.Count(result, "fake logs") != 1 {
+		t.Errorf("Expected the pod to be streamed once it started, got:\n%s", result)
+	}
+}
+
+func TestLogTailerFollowResumesLostWatch(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	first, expiring, last := watch.NewFake(), watch.NewFake(), watch.NewFake()
+	var mu sync.Mutex
+	var versions []string
+	clientset.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		versions = append(versions, action.(k8stesting.WatchAction).GetWatchRestrictions().ResourceVersion)
+		switch len(versions) {
+		case 1:
+			return true, first, nil
+		case 2:
+			return true, nil, fmt.Errorf("connection refused")
+		case 3:
+			return true, expiring, nil
+		}
+		return true, last, nil
+	})
+
+	var out syncBuffer
+	tailer := newLogTailer(clientset, "default", logTarget{selector: labels.Everything()}, logsOptions{follow: true, out: &out})
+	tailer.retryDelay = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- tailer.run(ctx) }()
+
+	pod := testLogPod("api-1", nil, "app")
+	pod.ResourceVersion = "7"
+	first.Add(pod)
+	waitForOutput(t, &out, "[api-1/app] fake logs")
+	first.Action(watch.Bookmark, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "9"}})
+	first.Stop()
+	waitForOutput(t, &out, "watch resumed at resourceVersion 9")
+
+	// The pod list has moved on while the watch fell behind
+	if err := clientset.Tracker().Add(testLogPod("api-2", nil, "app")); err != nil {
+		t.Fatalf("failed to add pod: %v", err)
+	}
+	expiring.Error(&metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusGone,
+		Reason:  metav1.StatusReasonExpired,
+		Message: "too old resource version: 9",
+	})
+	waitForOutput(t, &out, "[api-2/app] fake logs")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	result := out.String()
+	for _, expected := range []string{"watch closed", "connection refused", "expired (410 Gone), relisting pods"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected status %q, got:\n%s", expected, result)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(versions) < 4 || versions[1] != "9" || versions[2] != "9" {
+		t.Errorf("Expected the watch to resume from the bookmarked resourceVersion, got %q", versions)
+	}
+}
+
+func TestLogsOptionsPodLogOptions(t *testing.T) {
+	opts := logsOptions{
+		follow:     true,
+		tailLines:  -1,
+		previous:   true,
+		timestamps: true,
+		since:      15 * time.Minute,
+		limitBytes: 2048,
+	}
+	if err := opts.complete(); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+
+	logOpts := opts.podLogOptions("app")
+	if logOpts.Container != "app" || !logOpts.Follow || !logOpts.Previous || !logOpts.Timestamps {
+		t.Errorf("Unexpected log options: %+v", logOpts)
+	}
+	if logOpts.TailLines != nil {
+		t.Errorf("A negative --tail should request all lines, got %d", *logOpts.TailLines)
+	}
+	if logOpts.SinceSeconds == nil || *logOpts.SinceSeconds != 900 {
+		t.Errorf("Expected 900 since seconds, got %v", logOpts.SinceSeconds)
+	}
+	if logOpts.LimitBytes == nil || *logOpts.LimitBytes != 2048 {
+		t.Errorf("Expected a 2048 byte limit, got %v", logOpts.LimitBytes)
+	}
+
+	opts = logsOptions{tailLines: 20, sinceTime: "2024-01-02T15:04:05Z"}
+	if err := opts.complete(); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	logOpts = opts.podLogOptions("app")
+	if logOpts.TailLines == nil || *logOpts.TailLines != 20 {
+		t.Errorf("Expected 20 tail lines, got %v", logOpts.TailLines)
+	}
+	if logOpts.SinceTime == nil || !logOpts.SinceTime.Equal(&metav1.Time{Time: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)}) {
+		t.Errorf("Unexpected since time: %v", logOpts.SinceTime)
+	}
+}
+
+func TestLogsOptionsComplete(t *testing.T) {
+	invalid := []logsOptions{
+		{since: time.Minute, sinceTime: "2024-01-02T15:04:05Z"},
+		{since: -time.Minute},
+		{sinceTime: "yesterday"},
+		{limitBytes: -1},
+		{allContainers: true, container: "app"},
+	}
+	for _, opts := range invalid {
+		if err := opts.complete(); err == nil {
+			t.Errorf("complete(%+v) should fail", opts)
+		}
+	}
+}
+
+func TestSelectContainers(t *testing.T) {
+	pod := testLogPod("api-0", nil, "app", "sidecar")
+	pod.Spec.InitContainers = []corev1.Container{{Name: "migrate"}}
+	pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger"}}}
+
+	tests := []struct {
+		name     string
+		opts     logsOptions
+		expected []string
+	}{
+		{name: "default", expected: []string{"app", "sidecar"}},
+		{name: "all containers", opts: logsOptions{allContainers: true}, expected: []string{"migrate", "app", "sidecar", "debugger"}},
+		{name: "init container", opts: logsOptions{container: "migrate"}, expected: []string{"migrate"}},
+		{name: "ephemeral container", opts: logsOptions{container: "debugger"}, expected: []string{"debugger"}},
+		{name: "missing container", opts: logsOptions{container: "missing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectContainers(pod, tt.opts)
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCrashLoopHint(t *testing.T) {
+	pod := testLogPod("api-0", nil, "app", "sidecar")
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+	}
+	pod.Status.ContainerStatuses[0].RestartCount = 7
+
+	hint := crashLoopHint(pod, "app", logsOptions{})
+	for _, expected := range []string{"CrashLoopBackOff", "restarted 7 times", "--previous"} {
+		if !strings.Contains(hint, expected) {
+			t.Errorf("Expected %q in hint %q", expected, hint)
+		}
+	}
+	if hint := crashLoopHint(pod, "app", logsOptions{previous: true}); hint != "" {
+		t.Errorf("No hint expected with --previous, got %q", hint)
+	}
+	if hint := crashLoopHint(pod, "sidecar", logsOptions{}); hint != "" {
+		t.Errorf("No hint expected for a running container, got %q", hint)
+	}
+}
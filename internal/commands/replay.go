@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/errors"
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// replayOptions holds the flags of the replay command.
+type replayOptions struct {
+	speed   string
+	at      string
+	columns columnOptions
+
+	// out receives the table instead of stdout
+	out io.Writer
+}
+
+func (o replayOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+// NewReplayCommand creates a new replay command for recorded watch sessions.
+func NewReplayCommand() *cobra.Command {
+	var opts replayOptions
+
+	cmd := &cobra.Command{
+		Use:   "replay <recording>",
+		Short: "Replay a recorded watch session",
+		Long: `Replay a session recorded with 'k8ctl watch --record', rendering the same
+table as watch. No cluster is needed.
+
+The events are played back at their recorded pace, or faster with --speed;
+--at prints the table as it was at a point in time instead.`,
+		Example: `  k8ctl replay incident.jsonl --speed 10x
+  k8ctl replay incident.jsonl --at 14:03`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReplay(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.speed, "speed", "1x", "Playback speed, e.g. 10x")
+	cmd.Flags().StringVar(&opts.at, "at", "", "Print the table as of this time (15:04, 15:04:05 or RFC3339) instead of playing back")
+	addColumnFlags(cmd, &opts.columns)
+
+	return cmd
+}
+
+func runReplay(path string, opts replayOptions) error {
+	speed, err := parseReplaySpeed(opts.speed)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.WrapError(err, "Failed to open recording")
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	recording, err := readWatchRecording(file)
+	if err != nil {
+		return errors.WrapError(err, fmt.Sprintf("Failed to read recording %s", path))
+	}
+
+	if opts.at != "" {
+		at, err := parseReplayTime(opts.at, recording)
+		if err != nil {
+			return err
+		}
+		replaySnapshot(recording, at, opts)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	replayRecording(ctx, recording, speed, opts)
+	return nil
+}
+
+// replayState is the set of objects a recording describes at some point.
+type replayState struct {
+	objects   map[string]*unstructured.Unstructured
+	lastEvent string
+}
+
+func newReplayState() *replayState {
+	return &replayState{objects: map[string]*unstructured.Unstructured{}}
+}
+
+// apply updates the state with one recorded event.
+func (s *replayState) apply(record watchRecord) {
+	obj := &unstructured.Unstructured{Object: record.Object}
+	key := obj.GetName()
+	if obj.GetNamespace() != "" {
+		key = obj.GetNamespace() + "/" + key
+	}
+	if record.Type == "DELETED" {
+		delete(s.objects, key)
+	} else {
+		s.objects[key] = obj
+	}
+	if !record.Initial {
+		s.lastEvent = fmt.Sprintf("Event: %s - %s", record.Type, key)
+	}
+}
+
+func (s *replayState) list() []interface{} {
+	objects := make([]interface{}, 0, len(s.objects))
+	for _, obj := range s.objects {
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
+// replaySnapshot prints the table as it was at the given time.
+func replaySnapshot(recording *watchRecording, at time.Time, opts replayOptions) {
+	state := newReplayState()
+	for _, record := range recording.events {
+		// The initial list is recorded just after the session starts
+		if record.Time.After(at) && !record.Initial {
+			break
+		}
+		state.apply(record)
+	}
+
+	table := recording.session.table()
+	table.now = func() time.Time { return at }
+	lines := table.renderTable(state.list(), opts.columns)
+	lines = append(lines, "", fmt.Sprintf("State at %s", at.Format("2006-01-02 15:04:05")))
+	if state.lastEvent != "" {
+		lines = append(lines, "Last "+state.lastEvent)
+	}
+	_, _ = fmt.Fprintln(opts.writer(), strings.Join(lines, "\n"))
+}
+
+// replayRecording plays the events back, scaling the pauses between them by
+// speed, redrawing the table in place as watch does.
+func replayRecording(ctx context.Context, recording *watchRecording, speed float64, opts replayOptions) {
+	state := newReplayState()
+	clock := recording.start
+	table := recording.session.table()
+	table.now = func() time.Time { return clock }
+
+	screen := output.NewScreen(opts.writer())
+	draw := func(footer string) {
+		lines := table.renderTable(state.list(), opts.columns)
+		lines = append(lines, "", footer)
+		if state.lastEvent != "" {
+			lines = append(lines, state.lastEvent)
+		}
+		screen.Draw(lines)
+	}
+	playing := func() string {
+		return fmt.Sprintf("Replaying %s (%sx)... (Ctrl+C to stop)",
+			clock.Format("2006-01-02 15:04:05"), strconv.FormatFloat(speed, 'f', -1, 64))
+	}
+
+	for _, record := range recording.events {
+		// Events recorded together are drawn together
+		if pause := time.Duration(float64(record.Time.Sub(clock)) / speed); pause > 0 {
+			draw(playing())
+			select {
+			case <-ctx.Done():
+				_, _ = fmt.Fprintln(opts.writer(), "\nStopping replay...")
+				return
+			case <-time.After(pause):
+			}
+		}
+		if record.Time.After(clock) {
+			clock = record.Time
+		}
+		state.apply(record)
+	}
+	draw(fmt.Sprintf("Replay finished at %s", clock.Format("2006-01-02 15:04:05")))
+}
+
+// parseReplaySpeed parses a playback speed such as "10x" or "0.5".
+func parseReplaySpeed(value string) (float64, error) {
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(value), "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid --speed '%s', expected a positive factor such as 10x", value)
+	}
+	return speed, nil
+}
+
+// parseReplayTime parses --at as an RFC3339 time or a time of day during the
+// recording, which may span midnight.
+func parseReplayTime(value string, recording *watchRecording) (time.Time, error) {
+	start := recording.start
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		var clock time.Time
+		for _, layout := range []string{"15:04:05", "15:04"} {
+			if clock, err = time.Parse(layout, value); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --at '%s', expected 15:04, 15:04:05 or an RFC3339 time", value)
+		}
+		at = time.Date(start.Year(), start.Month(), start.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, start.Location())
+		// A time of day before the start refers to the next day of a
+		// recording that ran past midnight
+		if at.Before(start) && !at.Add(24*time.Hour).After(recording.end()) {
+			at = at.Add(24 * time.Hour)
+		}
+	}
+	if at.Before(start) {
+		// The minute the recording started in still shows its start
+		if at.Before(start.Truncate(time.Minute)) {
+			return time.Time{}, fmt.Errorf("--at %s is before the recording starts at %s", value, start.Format("2006-01-02 15:04:05"))
+		}
+		at = start
+	}
+	return at, nil
+}
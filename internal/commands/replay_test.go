@@ -0,0 +1,259 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+var replayStart = time.Date(2024, 1, 2, 14, 0, 0, 0, time.UTC)
+
+// recordTestSession records a pod that starts, a second pod added and the
+// first one deleted, a minute apart.
+func recordTestSession(t *testing.T) *watchRecording {
+	t.Helper()
+	res := &resolvedResource{gvr: podResource, kind: "Pod", namespaced: true}
+	now := replayStart
+	var buf bytes.Buffer
+	recorder := newWatchRecorder(&buf, false)
+	recorder.now = func() time.Time { return now }
+	if err := recorder.begin(newWatchSession(res, "default", newWatchTable(nil, res), watchOptions{})); err != nil {
+		t.Fatalf("failed to start recording: %v", err)
+	}
+	handler := recorder.handler()
+
+	pending := unstructuredPod(t, "api-0", corev1.PodPending)
+	pending.SetCreationTimestamp(metav1.NewTime(replayStart.Add(-5 * time.Minute)))
+	pending.SetResourceVersion("1")
+	running := pending.DeepCopy()
+	running.Object["status"].(map[string]interface{})["phase"] = "Running"
+	running.SetResourceVersion("2")
+
+	now = now.Add(100 * time.Millisecond)
+	handler.OnAdd(pending, true)
+	now = replayStart.Add(time.Minute)
+	handler.OnUpdate(pending, running)
+	// A relist repeats the object unchanged
+	now = replayStart.Add(90 * time.Second)
+	handler.OnUpdate(running, running.DeepCopy())
+	now = replayStart.Add(2 * time.Minute)
+	handler.OnAdd(unstructuredPod(t, "api-1", corev1.PodPending), false)
+	now = replayStart.Add(3 * time.Minute)
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/api-0", Obj: running})
+	if err := recorder.err(); err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+
+	recording, err := readWatchRecording(&buf)
+	if err != nil {
+		t.Fatalf("failed to read recording: %v", err)
+	}
+	return recording
+}
+
+func TestWatchRecordingSnapshot(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	recording := recordTestSession(t)
+	if len(recording.events) != 4 || !recording.start.Equal(replayStart) {
+		t.Fatalf("Expected 4 events from %s, got %d from %s", replayStart, len(recording.events), recording.start)
+	}
+
+	tests := []struct {
+		at       time.Time
+		expected []string
+		absent   []string
+	}{
+		{replayStart, []string{"api-0", "Pending", "5m"}, []string{"api-1", "Event:"}},
+		{replayStart.Add(90 * time.Second), []string{"Running", "6m", "Last Event: MODIFIED - default/api-0"}, []string{"api-1"}},
+		{replayStart.Add(time.Hour), []string{"api-1", "Last Event: DELETED - default/api-0"}, []string{"api-0 "}},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		replaySnapshot(recording, tt.at, replayOptions{out: &out})
+		result := out.String()
+		if !strings.Contains(result, "State at "+tt.at.Format("2006-01-02 15:04:05")) {
+			t.Errorf("Expected the snapshot time in:\n%s", result)
+		}
+		for _, expected := range tt.expected {
+			if !strings.Contains(result, expected) {
+				t.Errorf("At %s expected %q in:\n%s", tt.at.Format(time.Kitchen), expected, result)
+			}
+		}
+		for _, absent := range tt.absent {
+			if strings.Contains(result, absent) {
+				t.Errorf("At %s did not expect %q in:\n%s", tt.at.Format(time.Kitchen), absent, result)
+			}
+		}
+	}
+}
+
+func TestReplayRecording(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	var out bytes.Buffer
+	// Three recorded minutes take 180ms
+	replayRecording(context.Background(), recordTestSession(t), 1000, replayOptions{out: &out})
+
+	result := out.String()
+	for _, expected := range []string{
+		"Replaying 2024-01-02 14:00:00 (1000x)",
+		"Event: MODIFIED - default/api-0",
+		"Event: ADDED - default/api-1",
+		"Event: DELETED - default/api-0",
+		"Replay finished at 2024-01-02 14:03:00",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, result)
+		}
+	}
+}
+
+func TestWatchSessionTable(t *testing.T) {
+	client := newCertificateClient()
+	res := &resolvedResource{gvr: certificateResource, kind: "Certificate", namespaced: true}
+	table := newWatchTable(client, res)
+
+	data, err := json.Marshal(newWatchSession(res, "default", table, watchOptions{}))
+	if err != nil {
+		t.Fatalf("failed to marshal session: %v", err)
+	}
+	var session watchSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		t.Fatalf("failed to unmarshal session: %v", err)
+	}
+
+	replayed := session.table()
+	if strings.Join(replayed.headers, ",") != strings.Join(table.headers, ",") || replayed.kind != table.kind {
+		t.Errorf("Expected the recorded table %s %v, got %s %v", table.kind, table.headers, replayed.kind, replayed.headers)
+	}
+	cert, err := client.Resource(certificateResource).Namespace("default").Get(context.Background(), "api-tls", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get certificate: %v", err)
+	}
+	if row := replayed.row(cert); strings.Join(row[:3], ",") != "api-tls,True,api-tls" {
+		t.Errorf("Unexpected replayed row %v", row)
+	}
+}
+
+func TestReadWatchRecordingRejectsOtherFiles(t *testing.T) {
+	for _, input := range []string{
+		"",
+		`{"level":"info","msg":"not a recording"}`,
+		`{"time":"2024-01-02T14:00:00Z","type":"SESSION","session":{"format":99,"version":"v1","resource":"pods"}}`,
+	} {
+		if _, err := readWatchRecording(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected %q to be rejected", input)
+		}
+	}
+}
+
+func TestWatchRecorderRedactsSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.jsonl")
+	file, err := createRecording(path)
+	if err != nil {
+		t.Fatalf("createRecording failed: %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if info, err := file.Stat(); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0o600) {
+		t.Errorf("Expected the recording to be created with mode 0600, got %v, %v", info.Mode(), err)
+	}
+
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{"name": "db", "namespace": "default", "annotations": map[string]interface{}{
+			corev1.LastAppliedConfigAnnotation: `{"data":{"password":"aHVudGVyMg=="}}`,
+		}},
+		"data": map[string]interface{}{"password": "aHVudGVyMg=="},
+	}}
+	recorder := newWatchRecorder(file, false)
+	recorder.handler().OnAdd(secret, true)
+	if err := recorder.err(); err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+	if secret.Object["data"].(map[string]interface{})["password"] != "aHVudGVyMg==" {
+		t.Errorf("Expected the cached object to be left alone, got %v", secret.Object["data"])
+	}
+
+	var buf bytes.Buffer
+	newWatchRecorder(&buf, true).handler().OnAdd(secret, true)
+
+	recorded, _ := os.ReadFile(path)
+	if !strings.Contains(string(recorded), `"password":"<redacted: 7 bytes>"`) || strings.Contains(string(recorded), "aHVudGVyMg==") {
+		t.Errorf("Expected the secret value to be redacted, got %s", recorded)
+	}
+	if strings.Contains(string(recorded), corev1.LastAppliedConfigAnnotation) {
+		t.Errorf("Expected the last-applied annotation to be dropped, got %s", recorded)
+	}
+	if !strings.Contains(buf.String(), `"password":"aHVudGVyMg=="`) {
+		t.Errorf("Expected --show-secrets to record the value, got %s", buf.String())
+	}
+}
+
+func TestParseReplayTime(t *testing.T) {
+	start := time.Date(2024, 1, 2, 23, 50, 30, 0, time.UTC)
+	recording := &watchRecording{
+		start:  start,
+		events: []watchRecord{{Time: start.Add(30 * time.Minute)}},
+	}
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"23:55", time.Date(2024, 1, 2, 23, 55, 0, 0, time.UTC)},
+		{"23:50", start},
+		{"00:10:15", time.Date(2024, 1, 3, 0, 10, 15, 0, time.UTC)},
+		{"2024-01-03T00:05:00Z", time.Date(2024, 1, 3, 0, 5, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		at, err := parseReplayTime(tt.value, recording)
+		if err != nil {
+			t.Errorf("parseReplayTime(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if !at.Equal(tt.expected) {
+			t.Errorf("parseReplayTime(%q) = %s, expected %s", tt.value, at, tt.expected)
+		}
+	}
+
+	for _, value := range []string{"23:00", "noon", "25:00"} {
+		if _, err := parseReplayTime(value, recording); err == nil {
+			t.Errorf("Expected parseReplayTime(%q) to fail", value)
+		}
+	}
+}
+
+func TestParseReplaySpeed(t *testing.T) {
+	for value, expected := range map[string]float64{"1x": 1, "10x": 10, "0.5": 0.5, "2X": 2} {
+		if speed, err := parseReplaySpeed(value); err != nil || speed != expected {
+			t.Errorf("parseReplaySpeed(%q) = %v, %v; expected %v", value, speed, err, expected)
+		}
+	}
+	for _, value := range []string{"0x", "-2x", "fast"} {
+		if _, err := parseReplaySpeed(value); err == nil {
+			t.Errorf("Expected parseReplaySpeed(%q) to fail", value)
+		}
+	}
+}
@@ -12,6 +12,7 @@ func InitCommands(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(NewDescribeCommand())
 	rootCmd.AddCommand(NewLogsCommand())
 	rootCmd.AddCommand(NewWatchCommand())
+	rootCmd.AddCommand(NewReplayCommand())
 
 	// Context and namespace management
 	rootCmd.AddCommand(NewContextCommand())
@@ -18,6 +18,7 @@ import (
 func NewSearchCommand() *cobra.Command {
 	var namespace string
 	var resourceType string
+	var listOpts metav1.ListOptions
 
 	cmd := &cobra.Command{
 		Use:   "search [query]",
@@ -30,17 +31,19 @@ Supports searching across multiple resource types by name, labels, and annotatio
 			if len(args) > 0 {
 				query = args[0]
 			}
-			return runSearch(cmd, query, namespace, resourceType)
+			return runSearch(cmd, query, namespace, resourceType, listOpts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (overrides config)")
 	cmd.Flags().StringVarP(&resourceType, "type", "t", "", "Resource type to search (pods, services, etc.)")
+	cmd.Flags().StringVarP(&listOpts.LabelSelector, "selector", "l", "", "Label selector to filter on (e.g. app=api,tier!=cache)")
+	cmd.Flags().StringVar(&listOpts.FieldSelector, "field-selector", "", "Field selector to filter on (e.g. status.phase=Running)")
 
 	return cmd
 }
 
-func runSearch(_ *cobra.Command, query, namespace, resourceType string) error {
+func runSearch(_ *cobra.Command, query, namespace, resourceType string, listOpts metav1.ListOptions) error {
 	client, err := k8s.GetClient()
 	if err != nil {
 		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
@@ -75,15 +78,15 @@ func runSearch(_ *cobra.Command, query, namespace, resourceType string) error {
 
 	switch strings.ToLower(resourceType) {
 	case ResourcePods, ResourcePo:
-		return searchPods(client, namespace, query)
+		return searchPods(client, namespace, query, listOpts)
 	case ResourceDeployments, ResourceDeploy:
-		return searchDeployments(client, namespace, query)
+		return searchDeployments(client, namespace, query, listOpts)
 	case ResourceServices, ResourceSvc:
-		return searchServices(client, namespace, query)
+		return searchServices(client, namespace, query, listOpts)
 	case ResourceConfigMaps, ResourceCm:
-		return searchConfigMaps(client, namespace, query)
+		return searchConfigMaps(client, namespace, query, listOpts)
 	case ResourceSecrets, ResourceSec:
-		return searchSecrets(client, namespace, query)
+		return searchSecrets(client, namespace, query, listOpts)
 	default:
 		return errors.WrapError(
 			fmt.Errorf("resource type %s not yet implemented", resourceType),
@@ -92,8 +95,8 @@ func runSearch(_ *cobra.Command, query, namespace, resourceType string) error {
 	}
 }
 
-func searchPods(client kubernetes.Interface, namespace, _ string) error {
-	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+func searchPods(client kubernetes.Interface, namespace, _ string, listOpts metav1.ListOptions) error {
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), listOpts)
 	if err != nil {
 		return errors.WrapError(err, "Failed to list pods")
 	}
@@ -118,7 +121,7 @@ func searchPods(client kubernetes.Interface, namespace, _ string) error {
 			return fmt.Sprintf("Pod: %s\nNamespace: %s\nStatus: %s\nReady: %d/%d",
 				pod.Name,
 				pod.Namespace,
-				pod.Status.Phase,
+				getPodStatus(&pod),
 				getReadyContainers(&pod),
 				len(pod.Spec.Containers))
 		}),
@@ -135,8 +138,8 @@ func searchPods(client kubernetes.Interface, namespace, _ string) error {
 	return nil
 }
 
-func searchDeployments(client kubernetes.Interface, namespace, _ string) error {
-	deployments, err := client.AppsV1().Deployments(namespace).List(context.Background(), metav1.ListOptions{})
+func searchDeployments(client kubernetes.Interface, namespace, _ string, listOpts metav1.ListOptions) error {
+	deployments, err := client.AppsV1().Deployments(namespace).List(context.Background(), listOpts)
 	if err != nil {
 		return errors.WrapError(err, "Failed to list deployments")
 	}
@@ -178,8 +181,8 @@ func searchDeployments(client kubernetes.Interface, namespace, _ string) error {
 	return nil
 }
 
-func searchServices(client kubernetes.Interface, namespace, _ string) error {
-	services, err := client.CoreV1().Services(namespace).List(context.Background(), metav1.ListOptions{})
+func searchServices(client kubernetes.Interface, namespace, _ string, listOpts metav1.ListOptions) error {
+	services, err := client.CoreV1().Services(namespace).List(context.Background(), listOpts)
 	if err != nil {
 		return errors.WrapError(err, "Failed to list services")
 	}
@@ -224,8 +227,8 @@ func searchServices(client kubernetes.Interface, namespace, _ string) error {
 	return nil
 }
 
-func searchConfigMaps(client kubernetes.Interface, namespace, _ string) error {
-	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(context.Background(), metav1.ListOptions{})
+func searchConfigMaps(client kubernetes.Interface, namespace, _ string, listOpts metav1.ListOptions) error {
+	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(context.Background(), listOpts)
 	if err != nil {
 		return errors.WrapError(err, "Failed to list configmaps")
 	}
@@ -265,8 +268,8 @@ func searchConfigMaps(client kubernetes.Interface, namespace, _ string) error {
 	return nil
 }
 
-func searchSecrets(client kubernetes.Interface, namespace, _ string) error {
-	secrets, err := client.CoreV1().Secrets(namespace).List(context.Background(), metav1.ListOptions{})
+func searchSecrets(client kubernetes.Interface, namespace, _ string, listOpts metav1.ListOptions) error {
+	secrets, err := client.CoreV1().Secrets(namespace).List(context.Background(), listOpts)
 	if err != nil {
 		return errors.WrapError(err, "Failed to list secrets")
 	}
@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// certificateExpiryWarning is how soon before expiry a certificate is highlighted
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+// redactSecret returns secret as an unstructured object safe to print, see
+// redactSecretObject.
+func redactSecret(secret *corev1.Secret, decode bool) (map[string]interface{}, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to redact secret: %w", err)
+	}
+	// Typed clients leave apiVersion and kind empty
+	obj["apiVersion"], obj["kind"] = "v1", "Secret"
+	return obj, redactSecretObject(obj, decode)
+}
+
+// redactSecretObject redacts an unstructured object in place when it is a
+// core/v1 Secret; other objects are left untouched. Values stay under data,
+// replaced by a marker with their size that is not valid base64, so applying
+// the output fails rather than storing the marker. The last-applied
+// annotation kubectl apply leaves behind holds the same values, so it is
+// dropped. With decode, the values move to stringData in plain text instead.
+func redactSecretObject(obj map[string]interface{}, decode bool) error {
+	if !isSecretObject(obj) {
+		return nil
+	}
+	if !decode {
+		unstructured.RemoveNestedField(obj, "metadata", "annotations", corev1.LastAppliedConfigAnnotation)
+		if annotations, ok, _ := unstructured.NestedMap(obj, "metadata", "annotations"); ok && len(annotations) == 0 {
+			unstructured.RemoveNestedField(obj, "metadata", "annotations")
+		}
+	}
+	data, ok := obj["data"].(map[string]interface{})
+	if !ok || len(data) == 0 {
+		return nil
+	}
+
+	if !decode {
+		for key, value := range data {
+			data[key] = redactedValue(value)
+		}
+		return nil
+	}
+
+	stringData, _ := obj["stringData"].(map[string]interface{})
+	if stringData == nil {
+		stringData = make(map[string]interface{}, len(data))
+	}
+	for key, value := range data {
+		encoded, _ := value.(string)
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("failed to decode secret key %s: %w", key, err)
+		}
+		stringData[key] = string(decoded)
+	}
+	delete(obj, "data")
+	obj["stringData"] = stringData
+	return nil
+}
+
+// isSecretObject reports whether an unstructured object is a core/v1 Secret.
+func isSecretObject(obj map[string]interface{}) bool {
+	return obj["kind"] == "Secret" && obj["apiVersion"] == "v1"
+}
+
+// redactedValue replaces a base64 encoded value by its decoded size.
+func redactedValue(value interface{}) string {
+	encoded, _ := value.(string)
+	size := base64.StdEncoding.DecodedLen(len(encoded))
+	if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+		size = len(decoded)
+	}
+	return redactedMarker(size)
+}
+
+func redactedMarker(size int) string {
+	return fmt.Sprintf("<redacted: %d bytes>", size)
+}
+
+// describeSecretData lists each key with its size, or its decoded value.
+func describeSecretData(d *output.DescribeWriter, data map[string][]byte, decode bool) {
+	if !decode {
+		sizes := make(map[string]int, len(data))
+		for key, value := range data {
+			sizes[key] = len(value)
+		}
+		describeSizes(d, "Data", sizes)
+		return
+	}
+
+	values := make(map[string]string, len(data))
+	for key, value := range data {
+		values[key] = string(value)
+	}
+	describeValues(d, "Data", values)
+}
+
+// describeCertificate parses a PEM certificate chain, e.g. the tls.crt of a
+// kubernetes.io/tls secret, and describes the leaf certificate.
+func describeCertificate(d *output.DescribeWriter, data []byte) {
+	var chain []*x509.Certificate
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			d.Field(0, "Certificate", fmt.Sprintf("<unable to parse certificate: %v>", err))
+			return
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		d.Field(0, "Certificate", "<no PEM certificate found>")
+		return
+	}
+
+	cert := chain[0]
+	ips := make([]string, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+
+	d.Section(0, "Certificate")
+	d.Field(1, "Subject", cert.Subject.String())
+	d.Field(1, "Issuer", cert.Issuer.String())
+	d.Field(1, "DNS Names", strings.Join(cert.DNSNames, ", "))
+	if len(ips) > 0 {
+		d.Field(1, "IP Addresses", strings.Join(ips, ", "))
+	}
+	d.Field(1, "Not Before", cert.NotBefore.Format(time.RFC1123Z))
+	d.Field(1, "Not After", fmt.Sprintf("%s (%s)", cert.NotAfter.Format(time.RFC1123Z), formatExpiry(cert.NotAfter, time.Now())))
+	if len(chain) > 1 {
+		d.Field(1, "Chain Length", fmt.Sprintf("%d", len(chain)))
+	}
+}
+
+// formatExpiry describes how far away an expiry is, colored once it is close
+// or past.
+func formatExpiry(notAfter, now time.Time) string {
+	remaining := notAfter.Sub(now)
+	switch {
+	case remaining <= 0:
+		return output.StatusFailed.Sprintf("expired %s ago", duration.HumanDuration(-remaining))
+	case remaining < certificateExpiryWarning:
+		return output.StatusWarning.Sprintf("expires in %s", duration.HumanDuration(remaining))
+	default:
+		return fmt.Sprintf("expires in %s", duration.HumanDuration(remaining))
+	}
+}
+
+// dockerAuth is one registry entry of a docker config; the password is
+// deliberately not decoded.
+type dockerAuth struct {
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// describeRegistries lists the registries of a .dockerconfigjson (or legacy
+// .dockercfg) secret with their usernames, never their passwords.
+func describeRegistries(d *output.DescribeWriter, data []byte, legacy bool) {
+	var auths map[string]dockerAuth
+	var err error
+	if legacy {
+		err = json.Unmarshal(data, &auths)
+	} else {
+		var config struct {
+			Auths map[string]dockerAuth `json:"auths"`
+		}
+		err = json.Unmarshal(data, &config)
+		auths = config.Auths
+	}
+	if err != nil {
+		d.Field(0, "Registries", fmt.Sprintf("<unable to parse docker config: %v>", err))
+		return
+	}
+	if len(auths) == 0 {
+		d.Field(0, "Registries", "")
+		return
+	}
+
+	registries := make([]string, 0, len(auths))
+	for registry := range auths {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	d.Section(0, "Registries")
+	for _, registry := range registries {
+		auth := auths[registry]
+		username := auth.Username
+		if username == "" && auth.Auth != "" {
+			// auth is base64("username:password"); keep only the username
+			if decoded, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil {
+				username, _, _ = strings.Cut(string(decoded), ":")
+			}
+		}
+		d.Section(1, registry)
+		d.Field(2, "Username", username)
+		if auth.Email != "" {
+			d.Field(2, "Email", auth.Email)
+		}
+	}
+}
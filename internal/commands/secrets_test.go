@@ -0,0 +1,268 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRedactSecret(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{"password": []byte("hunter2")}}
+
+	redacted, err := redactSecret(secret, false)
+	if err != nil {
+		t.Fatalf("redactSecret failed: %v", err)
+	}
+	data, _ := redacted["data"].(map[string]interface{})
+	if data["password"] != "<redacted: 7 bytes>" || redacted["stringData"] != nil {
+		t.Errorf("Expected a redacted value under data, got %v", redacted)
+	}
+	if redacted["kind"] != "Secret" || redacted["apiVersion"] != "v1" {
+		t.Errorf("Expected apiVersion and kind to be set, got %v", redacted)
+	}
+	if string(secret.Data["password"]) != "hunter2" {
+		t.Error("redactSecret should not modify its input")
+	}
+
+	decoded, err := redactSecret(secret, true)
+	if err != nil {
+		t.Fatalf("redactSecret failed: %v", err)
+	}
+	stringData, _ := decoded["stringData"].(map[string]interface{})
+	if stringData["password"] != "hunter2" || decoded["data"] != nil {
+		t.Errorf("Expected the decoded value under stringData, got %v", decoded)
+	}
+}
+
+func TestRedactSecretObject(t *testing.T) {
+	secret := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "creds"},
+		"data":       map[string]interface{}{"password": base64.StdEncoding.EncodeToString([]byte("hunter2"))},
+	}
+	if err := redactSecretObject(secret, false); err != nil {
+		t.Fatalf("redactSecretObject failed: %v", err)
+	}
+	data, _ := secret["data"].(map[string]interface{})
+	if data["password"] != "<redacted: 7 bytes>" {
+		t.Errorf("Expected a redacted value, got %v", secret["data"])
+	}
+	// The marker must never be mistaken for a value by kubectl apply
+	if _, err := base64.StdEncoding.DecodeString(data["password"].(string)); err == nil {
+		t.Error("Expected the redaction marker not to be valid base64")
+	}
+
+	configMap := map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "data": map[string]interface{}{"key": "value"}}
+	if err := redactSecretObject(configMap, false); err != nil || configMap["data"].(map[string]interface{})["key"] != "value" {
+		t.Errorf("Other kinds should be left untouched, got %v (%v)", configMap, err)
+	}
+}
+
+func TestGetSecretsStructured(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	})
+	encoded := base64.StdEncoding.EncodeToString([]byte("hunter2"))
+
+	// Structured output stays applyable and scriptable, like kubectl's
+	var buf bytes.Buffer
+	if err := getSecrets(clientset, "default", "creds", getOptions{outputFormat: "yaml", out: &buf}); err != nil {
+		t.Fatalf("getSecrets failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "data:\n  password: "+encoded) {
+		t.Errorf("Expected the base64 value under data, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := getSecrets(clientset, "default", "creds", getOptions{outputFormat: "jsonpath={.data.password}", out: &buf}); err != nil {
+		t.Fatalf("getSecrets failed: %v", err)
+	}
+	if buf.String() != encoded {
+		t.Errorf("Expected the base64 value from jsonpath, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := getSecrets(clientset, "default", "creds", getOptions{outputFormat: "yaml", decode: true, out: &buf}); err != nil {
+		t.Fatalf("getSecrets failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "stringData:\n  password: hunter2") || strings.Contains(buf.String(), encoded) {
+		t.Errorf("Expected decoded output with --decode, got:\n%s", buf.String())
+	}
+}
+
+func TestGetGenericSecret(t *testing.T) {
+	secretResource := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	encoded := base64.StdEncoding.EncodeToString([]byte("hunter2"))
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{secretResource: "SecretList"},
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": "db", "namespace": "default"},
+			"data":       map[string]interface{}{"password": encoded},
+		}},
+	)
+	res := &resolvedResource{gvr: secretResource, kind: "Secret", namespaced: true}
+
+	for _, name := range []string{"db", ""} {
+		var buf bytes.Buffer
+		if err := getGeneric(client, res, "default", name, getOptions{outputFormat: "yaml", decode: true, out: &buf}); err != nil {
+			t.Fatalf("getGeneric failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), "password: hunter2") || strings.Contains(buf.String(), encoded) {
+			t.Errorf("Expected decoded output for %q with --decode, got:\n%s", name, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := getGeneric(client, res, "default", "db", getOptions{outputFormat: "yaml", out: &buf}); err != nil {
+		t.Fatalf("getGeneric failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "password: "+encoded) {
+		t.Errorf("Expected the base64 value under data, got:\n%s", buf.String())
+	}
+
+	// The cached object must not be modified by decoding
+	obj, err := client.Resource(secretResource).Namespace("default").Get(context.Background(), "db", metav1.GetOptions{})
+	if err != nil || obj.Object["data"].(map[string]interface{})["password"] != encoded {
+		t.Errorf("Expected the stored secret to be unchanged, got %v (%v)", obj, err)
+	}
+}
+
+// appliedSecret is a secret created by kubectl apply, whose last-applied
+// annotation repeats its data.
+func appliedSecret() *corev1.Secret {
+	encoded := base64.StdEncoding.EncodeToString([]byte("hunter2"))
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default", Annotations: map[string]string{
+			corev1.LastAppliedConfigAnnotation: `{"apiVersion":"v1","data":{"password":"` + encoded + `"},"kind":"Secret","metadata":{"name":"creds","namespace":"default"}}`,
+			"team":                             "payments",
+		}},
+		Data: map[string][]byte{"password": []byte("hunter2")},
+	}
+}
+
+func TestRedactAppliedSecret(t *testing.T) {
+	redacted, err := redactSecret(appliedSecret(), false)
+	if err != nil {
+		t.Fatalf("redactSecret failed: %v", err)
+	}
+	annotations, _, _ := unstructured.NestedStringMap(redacted, "metadata", "annotations")
+	if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok || annotations["team"] != "payments" {
+		t.Errorf("Expected only the last-applied annotation to be dropped, got %v", annotations)
+	}
+
+	only := appliedSecret()
+	delete(only.Annotations, "team")
+	if redacted, _ = redactSecret(only, false); redacted["metadata"].(map[string]interface{})["annotations"] != nil {
+		t.Errorf("Expected no annotations to be left, got %v", redacted["metadata"])
+	}
+}
+
+func TestDescribeAppliedSecret(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+	clientset := fake.NewSimpleClientset(appliedSecret())
+	encoded := base64.StdEncoding.EncodeToString([]byte("hunter2"))
+
+	for _, format := range []string{"", "yaml", "json"} {
+		var buf bytes.Buffer
+		if err := describeSecret(clientset, "default", "creds", describeOptions{outputFormat: format, out: &buf}); err != nil {
+			t.Fatalf("describeSecret failed: %v", err)
+		}
+		if strings.Contains(buf.String(), encoded) || strings.Contains(buf.String(), "hunter2") ||
+			strings.Contains(buf.String(), corev1.LastAppliedConfigAnnotation) {
+			t.Errorf("Expected no secret values with -o %q, got:\n%s", format, buf.String())
+		}
+		if !strings.Contains(buf.String(), "payments") {
+			t.Errorf("Expected the other annotations with -o %q, got:\n%s", format, buf.String())
+		}
+	}
+}
+
+func TestDescribeTLSSecret(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "api.example.com"},
+		DNSNames:     []string{"api.example.com", "www.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(10 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-tls", Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: cert, corev1.TLSPrivateKeyKey: []byte("private")},
+	})
+
+	var buf bytes.Buffer
+	if err := describeSecret(clientset, "default", "api-tls", describeOptions{out: &buf}); err != nil {
+		t.Fatalf("describeSecret failed: %v", err)
+	}
+	assertContains(t, buf.String(), "Certificate:", "CN=api.example.com", "www.example.com", "Not After:", "expires in 9d", "tls.key:")
+	if strings.Contains(buf.String(), "private") {
+		t.Errorf("The private key must not be printed, got:\n%s", buf.String())
+	}
+}
+
+func TestDescribeRegistries(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:s3cret"))
+	config := `{"auths":{"registry.example.com":{"auth":"` + auth + `"},"ghcr.io":{"username":"ci","password":"token","email":"ci@example.com"}}}`
+
+	var buf bytes.Buffer
+	d := output.NewDescribeWriter(&buf)
+	describeRegistries(d, []byte(config), false)
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	assertContains(t, buf.String(), "ghcr.io", "ci@example.com", "registry.example.com", "robot")
+	for _, secret := range []string{"s3cret", "token", auth} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("Registry passwords must not be printed, found %q in:\n%s", secret, buf.String())
+		}
+	}
+}
+
+func TestFormatExpiry(t *testing.T) {
+	now := time.Now()
+	if got := formatExpiry(now.Add(-48*time.Hour), now); !strings.Contains(got, "expired 2d") {
+		t.Errorf("Expected an expired certificate, got %q", got)
+	}
+	if got := formatExpiry(now.Add(90*24*time.Hour), now); !strings.Contains(got, "expires in 90d") {
+		t.Errorf("Expected expiry in 90d, got %q", got)
+	}
+}
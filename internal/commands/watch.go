@@ -1,49 +1,155 @@
 package commands
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/robertusnegoro/k8ctl/internal/config"
 	"github.com/robertusnegoro/k8ctl/internal/errors"
 	"github.com/robertusnegoro/k8ctl/internal/k8s"
 	"github.com/robertusnegoro/k8ctl/internal/output"
 	"github.com/spf13/cobra"
-	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
 )
 
+const (
+	// watchDebounce is how long watch waits for further changes before redrawing
+	watchDebounce = 250 * time.Millisecond
+	// watchRefresh redraws without changes so ages stay current
+	watchRefresh = 5 * time.Second
+)
+
+// watchOptions holds the flags that select and shape watched resources.
+type watchOptions struct {
+	listOpts metav1.ListOptions
+	columns  columnOptions
+	debounce time.Duration
+	// outputFormat prints events instead of the table: events or jsonl
+	outputFormat string
+	// showSecrets prints secret values instead of redacting them
+	showSecrets bool
+	// record is the file every event is recorded to for replay
+	record   string
+	recorder *watchRecorder
+
+	// hookOn are the rules that run hookExec and/or POST to hookWebhook
+	hookOn       []string
+	hookRules    []watchRule
+	hookExec     string
+	hookWebhook  string
+	hookCooldown time.Duration
+	hookRate     int
+
+	// out receives the table instead of stdout
+	out io.Writer
+}
+
+func (o watchOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+// watchTable renders one resource type from the objects in the informer cache.
+type watchTable struct {
+	kind    string
+	headers []string
+	row     func(obj *unstructured.Unstructured) []string
+	// columns are the printer columns behind row, if any; recordings keep
+	// them so a replay renders the same table
+	columns []printerColumn
+	// now, when set, is the clock ages are shown against instead of the
+	// current time
+	now func() time.Time
+}
+
 // NewWatchCommand creates a new watch command for watching Kubernetes resources.
 func NewWatchCommand() *cobra.Command {
 	var namespace string
+	var opts watchOptions
 
 	cmd := &cobra.Command{
 		Use:   "watch [resource-type]",
 		Short: "Watch resources for changes",
-		Long: `Watch resources and display updates in real-time with
-color changes on state transitions.`,
+		Long: `Watch any resource type, including custom resources, and display
+updates in real-time with color changes on state transitions.
+
+Objects are kept in a local cache fed by a single watch, so changes cost no
+extra API calls; the table is redrawn in place, rewriting only changed rows.
+
+With -o events, one line is printed per change instead, listing the fields
+that changed; -o jsonl prints the same as JSON records. Changed secret values
+are redacted unless --show-secrets is set.
+
+--record saves every event with its full object, to be replayed later with
+'k8ctl replay'. The file is only readable by its owner and secret values are
+redacted unless --show-secrets is set.
+
+--on runs --exec or --webhook when an object starts matching a rule. A rule
+compares the event type, table columns or object fields, e.g.
+'status=CrashLoopBackOff', 'type=DELETED' or 'restarts>3,metadata.labels.app=api';
+keys containing dots go in brackets, e.g. 'metadata.labels[app.kubernetes.io/name]=api'.
+Commands get the object as JSON on stdin, with secret values redacted unless
+--show-secrets is set, and K8CTL_EVENT, K8CTL_RULE, K8CTL_KIND,
+K8CTL_NAMESPACE and K8CTL_NAME in their environment.`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runWatch(cmd, args, namespace)
+			return runWatch(cmd, args, namespace, opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (overrides config)")
+	cmd.Flags().StringVarP(&opts.listOpts.LabelSelector, "selector", "l", "", "Label selector to filter on (e.g. app=api,tier!=cache)")
+	cmd.Flags().StringVar(&opts.listOpts.FieldSelector, "field-selector", "", "Field selector to filter on (e.g. status.phase=Running)")
+	cmd.Flags().DurationVar(&opts.debounce, "debounce", watchDebounce, "How long to wait for further changes before redrawing")
+	cmd.Flags().StringVarP(&opts.outputFormat, "output", "o", "", "Output format: events or jsonl print one line per change instead of the table")
+	cmd.Flags().BoolVar(&opts.showSecrets, "show-secrets", false, "Show secret values in -o events, jsonl, --record and hooks instead of redacting them")
+	cmd.Flags().StringVar(&opts.record, "record", "", "Record every event with its full object to this file, for 'k8ctl replay'")
+	cmd.Flags().StringArrayVar(&opts.hookOn, "on", nil, "Rule that runs the hooks when an object starts matching it, e.g. status=CrashLoopBackOff (repeatable)")
+	cmd.Flags().StringVar(&opts.hookExec, "exec", "", "Shell command to run when a rule matches")
+	cmd.Flags().StringVar(&opts.hookWebhook, "webhook", "", "URL to POST the matching event to as JSON")
+	cmd.Flags().DurationVar(&opts.hookCooldown, "hook-cooldown", watchHookCooldown, "How long a rule stays quiet for an object after it fired")
+	cmd.Flags().IntVar(&opts.hookRate, "hook-rate", watchHookRate, "Maximum number of hooks to run per minute")
+	addColumnFlags(cmd, &opts.columns)
 
 	return cmd
 }
 
-func runWatch(_ *cobra.Command, args []string, namespace string) error {
-	resourceType := args[0]
+func runWatch(_ *cobra.Command, args []string, namespace string, opts watchOptions) error {
+	if opts.outputFormat != "" && opts.outputFormat != OutputFormatEvents && opts.outputFormat != OutputFormatJSONL {
+		return fmt.Errorf("unsupported output format '%s' for watch, use events or jsonl", opts.outputFormat)
+	}
+	if err := opts.completeHooks(); err != nil {
+		return err
+	}
 
-	client, err := k8s.GetClient()
+	mapper, err := k8s.GetRESTMapper()
+	if err != nil {
+		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
+	}
+	res, err := lookupResource(mapper, args[0])
+	if err != nil {
+		return err
+	}
+	client, err := k8s.GetDynamicClient()
 	if err != nil {
 		return errors.WrapError(err, "Failed to connect to Kubernetes cluster")
 	}
@@ -55,398 +161,463 @@ func runWatch(_ *cobra.Command, args []string, namespace string) error {
 			namespace = DefaultNamespace
 		}
 	}
-
-	// Expand shortcuts
-	resourceShortcuts := map[string]string{
-		"po":     "pods",
-		"deploy": "deployments",
-		"svc":    "services",
-		"ing":    "ingresses",
-		"cm":     "configmaps",
-		"sec":    "secrets",
-		"sa":     "serviceaccounts",
-	}
-	if expanded, ok := resourceShortcuts[strings.ToLower(resourceType)]; ok {
-		resourceType = expanded
-	}
-
-	switch strings.ToLower(resourceType) {
-	case ResourcePods, ResourcePo:
-		return watchPods(client, namespace)
-	case ResourceDeployments, ResourceDeploy:
-		return watchDeployments(client, namespace)
-	case ResourceServices, ResourceSvc:
-		return watchServices(client, namespace)
-	case ResourceConfigMaps, ResourceCm:
-		return watchConfigMaps(client, namespace)
-	case ResourceSecrets, ResourceSec:
-		return watchSecrets(client, namespace)
-	default:
-		return errors.WrapError(
-			fmt.Errorf("resource type %s not yet implemented", resourceType),
-			fmt.Sprintf("Resource type '%s' is not yet supported for watch", resourceType),
-		)
+	if !res.namespaced {
+		namespace = ""
 	}
-}
 
-func watchPods(client kubernetes.Interface, namespace string) error {
-	watcher, err := client.CoreV1().Pods(namespace).Watch(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		return errors.WrapError(err, "Failed to create watcher")
+	table := newWatchTable(client, res)
+	if opts.record != "" {
+		file, err := createRecording(opts.record)
+		if err != nil {
+			return errors.WrapError(err, "Failed to create recording")
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		opts.recorder = newWatchRecorder(file, opts.showSecrets)
+		if err := opts.recorder.begin(newWatchSession(res, namespace, table, opts)); err != nil {
+			return errors.WrapError(err, "Failed to write recording")
+		}
 	}
-	defer watcher.Stop()
-
-	// Handle interrupt signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	// Initial display
-	displayPods(client, namespace)
 
-	fmt.Println("\nWatching for changes... (Ctrl+C to stop)")
-
-	// Watch for events
-	for {
-		select {
-		case event, ok := <-watcher.ResultChan():
-			if !ok {
-				return errors.WrapError(fmt.Errorf("watch channel closed"), "Watch connection lost")
-			}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return watchResource(ctx, client, res, namespace, table, opts)
+}
 
-			switch event.Type {
-			case watch.Added, watch.Modified, watch.Deleted:
-				// Clear screen and redisplay
-				fmt.Print("\033[2J\033[H")
-				displayPods(client, namespace)
-				pod, ok := event.Object.(*corev1.Pod)
-				if ok {
-					fmt.Printf("\nEvent: %s - %s\n", event.Type, pod.Name)
-				}
-			}
+// newWatchTable picks the columns for a resource type: the familiar ones for
+// common types, otherwise the printer columns of custom resources.
+func newWatchTable(client dynamic.Interface, res *resolvedResource) watchTable {
+	if table, ok := builtinWatchTable(res); ok {
+		return table
+	}
+	return printerColumnsWatchTable(res, getPrinterColumns(client, res, false))
+}
 
-		case <-sigChan:
-			fmt.Println("\nStopping watch...")
-			return nil
+// builtinWatchTable returns the table of a common resource type: the columns
+// get prints for the types it lists itself, otherwise those kubectl prints.
+func builtinWatchTable(res *resolvedResource) (watchTable, bool) {
+	kind := displayKind(res.kind, res.gvr.Group)
+	switch res.gvr.GroupResource() {
+	case schema.GroupResource{Resource: ResourcePods}:
+		return getColumnsWatchTable(kind, podHeaders, podRow), true
+	case schema.GroupResource{Group: "apps", Resource: ResourceDeployments}:
+		return getColumnsWatchTable(kind, deploymentHeaders, deploymentRow), true
+	case schema.GroupResource{Resource: ResourceServices}:
+		return getColumnsWatchTable(kind, serviceHeaders, serviceRow), true
+	case schema.GroupResource{Resource: ResourceNamespaces}:
+		return getColumnsWatchTable(kind, namespaceHeaders, namespaceRow), true
+	case schema.GroupResource{Resource: ResourceNodes}:
+		return getColumnsWatchTable(kind, nodeHeaders, nodeRow), true
+	case schema.GroupResource{Resource: ResourceConfigMaps}:
+		return getColumnsWatchTable(kind, configMapHeaders, configMapRow), true
+	case schema.GroupResource{Resource: ResourceSecrets}:
+		return getColumnsWatchTable(kind, secretHeaders, secretRow), true
+	case schema.GroupResource{Resource: ResourceServiceAccounts}:
+		return getColumnsWatchTable(kind, serviceAccountHeaders, serviceAccountRow), true
+	case schema.GroupResource{Group: "networking.k8s.io", Resource: ResourceIngresses}:
+		return getColumnsWatchTable(kind, ingressHeaders, ingressRow), true
+	case schema.GroupResource{Group: "apps", Resource: ResourceStatefulSets}:
+		return typedWatchTable(kind, []string{"NAME", "READY", "AGE"}, statefulSetWatchRow), true
+	case schema.GroupResource{Group: "apps", Resource: ResourceDaemonSets}:
+		return typedWatchTable(kind, []string{"NAME", "DESIRED", "CURRENT", "READY", "UP-TO-DATE", "AVAILABLE", "NODE SELECTOR", "AGE"}, daemonSetWatchRow), true
+	case schema.GroupResource{Group: "apps", Resource: ResourceReplicaSets}:
+		return typedWatchTable(kind, []string{"NAME", "DESIRED", "CURRENT", "READY", "AGE"}, replicaSetWatchRow), true
+	case schema.GroupResource{Group: "batch", Resource: ResourceJobs}:
+		return typedWatchTable(kind, []string{"NAME", "COMPLETIONS", "DURATION", "AGE"}, jobWatchRow), true
+	case schema.GroupResource{Group: "batch", Resource: ResourceCronJobs}:
+		return typedWatchTable(kind, []string{"NAME", "SCHEDULE", "TIMEZONE", "SUSPEND", "ACTIVE", "LAST SCHEDULE", "AGE"}, cronJobWatchRow), true
+	case schema.GroupResource{Resource: ResourcePersistentVolumeClaims}:
+		return typedWatchTable(kind, []string{"NAME", "STATUS", "VOLUME", "CAPACITY", "ACCESS MODES", "STORAGECLASS", "AGE"}, claimWatchRow), true
+	case schema.GroupResource{Resource: ResourcePersistentVolumes}:
+		return typedWatchTable(kind, []string{"NAME", "CAPACITY", "ACCESS MODES", "RECLAIM POLICY", "STATUS", "CLAIM", "STORAGECLASS", "REASON", "AGE"}, volumeWatchRow), true
+	case schema.GroupResource{Group: "autoscaling", Resource: ResourceHorizontalPodAutoscalers}:
+		// Older versions lack the metrics the TARGETS column shows
+		if res.gvr.Version == "v2" {
+			return typedWatchTable(kind, []string{"NAME", "REFERENCE", "TARGETS", "MINPODS", "MAXPODS", "REPLICAS", "AGE"}, autoscalerWatchRow), true
 		}
 	}
+	return watchTable{}, false
 }
 
-func displayPods(client kubernetes.Interface, namespace string) {
-	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		fmt.Printf("Error listing pods: %v\n", err)
-		return
+// printerColumnsWatchTable renders any resource through printer columns.
+func printerColumnsWatchTable(res *resolvedResource, columns []printerColumn) watchTable {
+	headers := []string{"NAME"}
+	for _, col := range columns {
+		headers = append(headers, strings.ToUpper(col.name))
 	}
-
-	table := output.NewTable([]string{"NAME", "READY", "STATUS", "RESTARTS", "AGE"})
-	for i := range pods.Items {
-		pod := &pods.Items[i]
-		ready := fmt.Sprintf("%d/%d", getReadyContainers(pod), len(pod.Spec.Containers))
-		status := getPodStatus(pod)
-		restarts := getRestartCount(pod)
-		age := getAge(pod.CreationTimestamp)
-
-		table.AddRow([]string{
-			pod.Name,
-			ready,
-			status,
-			fmt.Sprintf("%d", restarts),
-			age,
-		})
+	return watchTable{
+		kind:    displayKind(res.kind, res.gvr.Group),
+		headers: headers,
+		row: func(obj *unstructured.Unstructured) []string {
+			row := []string{obj.GetName()}
+			for i := range columns {
+				row = append(row, columns[i].value(obj))
+			}
+			return row
+		},
+		columns: columns,
 	}
-	table.Render()
 }
 
-func watchDeployments(client kubernetes.Interface, namespace string) error {
-	watcher, err := client.AppsV1().Deployments(namespace).Watch(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		return errors.WrapError(err, "Failed to create watcher")
+// typedWatchTable renders cached objects through a row function of their
+// typed API struct.
+func typedWatchTable[T any](kind string, headers []string, row func(*T) []string) watchTable {
+	return watchTable{
+		kind:    kind,
+		headers: headers,
+		row: func(obj *unstructured.Unstructured) []string {
+			typed := new(T)
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+				return []string{obj.GetName(), fmt.Sprintf("<invalid: %v>", err)}
+			}
+			return row(typed)
+		},
 	}
-	defer watcher.Stop()
-
-	// Handle interrupt signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	// Initial display
-	displayDeployments(client, namespace)
-
-	fmt.Println("\nWatching for changes... (Ctrl+C to stop)")
+}
 
-	// Watch for events
-	for {
-		select {
-		case event, ok := <-watcher.ResultChan():
-			if !ok {
-				return errors.WrapError(fmt.Errorf("watch channel closed"), "Watch connection lost")
-			}
+// getColumnsWatchTable renders cached objects with the columns get prints
+// without -o wide.
+func getColumnsWatchTable[T any](kind string, headers func(wide bool) []string, row func(item *T, wide bool) []string) watchTable {
+	return typedWatchTable(kind, headers(false), func(item *T) []string { return row(item, false) })
+}
 
-			switch event.Type {
-			case watch.Added, watch.Modified, watch.Deleted:
-				// Clear screen and redisplay
-				fmt.Print("\033[2J\033[H")
-				displayDeployments(client, namespace)
-				deployment, ok := event.Object.(*appsv1.Deployment)
-				if ok {
-					fmt.Printf("\nEvent: %s - %s\n", event.Type, deployment.Name)
-				}
-			}
+// watchConnection tracks the state of the watch feeding the cache for the
+// status line below the table. The reflector does the recovery: it resumes
+// from the last seen resourceVersion, requests bookmarks so that version stays
+// fresh, backs off between attempts and relists after 410 Gone.
+type watchConnection struct {
+	mu sync.Mutex
+	// reason describes the latest outage, down is set while it lasts
+	reason     string
+	down       bool
+	since      time.Time
+	reconnects int
+	recovered  time.Time
+	// changed asks for a redraw
+	changed func()
+}
 
-		case <-sigChan:
-			fmt.Println("\nStopping watch...")
-			return nil
-		}
+// lost records an outage, keeping the time it started.
+func (c *watchConnection) lost(reason string) {
+	c.mu.Lock()
+	if !c.down {
+		c.since = time.Now()
 	}
+	c.reason = reason
+	c.down = true
+	c.mu.Unlock()
+	c.changed()
 }
 
-func displayDeployments(client kubernetes.Interface, namespace string) {
-	deployments, err := client.AppsV1().Deployments(namespace).List(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		fmt.Printf("Error listing deployments: %v\n", err)
+// connected ends the current outage, if any.
+func (c *watchConnection) connected() {
+	c.mu.Lock()
+	if !c.down {
+		c.mu.Unlock()
 		return
 	}
-
-	table := output.NewTable([]string{"NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE"})
-	for i := range deployments.Items {
-		deployment := &deployments.Items[i]
-		ready := fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, deployment.Status.Replicas)
-		upToDate := fmt.Sprintf("%d", deployment.Status.UpdatedReplicas)
-		available := fmt.Sprintf("%d", deployment.Status.AvailableReplicas)
-		age := getAge(deployment.CreationTimestamp)
-
-		table.AddRow([]string{
-			deployment.Name,
-			ready,
-			upToDate,
-			available,
-			age,
-		})
-	}
-	table.Render()
+	c.down = false
+	c.reconnects++
+	c.recovered = time.Now()
+	c.mu.Unlock()
+	c.changed()
 }
 
-func watchServices(client kubernetes.Interface, namespace string) error {
-	watcher, err := client.CoreV1().Services(namespace).Watch(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		return errors.WrapError(err, "Failed to create watcher")
+// line returns the status line, empty until the first outage.
+func (c *watchConnection) line() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.down {
+		return output.Warning.Sprintf("Reconnecting after %s (down %s)...", c.reason, time.Since(c.since).Round(time.Second))
 	}
-	defer watcher.Stop()
-
-	// Handle interrupt signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	// Initial display
-	displayServices(client, namespace)
-
-	fmt.Println("\nWatching for changes... (Ctrl+C to stop)")
+	if c.reconnects == 0 {
+		return ""
+	}
+	plural := "s"
+	if c.reconnects == 1 {
+		plural = ""
+	}
+	return output.Dim.Sprintf("Reconnected at %s after %s, %d reconnect%s so far",
+		c.recovered.Format("15:04:05"), c.reason, c.reconnects, plural)
+}
 
-	// Watch for events
-	for {
-		select {
-		case event, ok := <-watcher.ResultChan():
-			if !ok {
-				return errors.WrapError(fmt.Errorf("watch channel closed"), "Watch connection lost")
+// listWatch lists and watches the resource, reporting failures, closed
+// watches and expired resource versions to the connection state.
+func (c *watchConnection) listWatch(ctx context.Context, resource dynamic.ResourceInterface, selectors metav1.ListOptions) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = selectors.LabelSelector
+			options.FieldSelector = selectors.FieldSelector
+			list, err := resource.List(ctx, options)
+			if err == nil {
+				c.connected()
 			}
-
-			switch event.Type {
-			case watch.Added, watch.Modified, watch.Deleted:
-				// Clear screen and redisplay
-				fmt.Print("\033[2J\033[H")
-				displayServices(client, namespace)
-				service, ok := event.Object.(*corev1.Service)
-				if ok {
-					fmt.Printf("\nEvent: %s - %s\n", event.Type, service.Name)
+			return list, err
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = selectors.LabelSelector
+			options.FieldSelector = selectors.FieldSelector
+			options.AllowWatchBookmarks = true
+			w, err := resource.Watch(ctx, options)
+			if err != nil {
+				if ctx.Err() == nil {
+					c.lost(fmt.Sprintf("connection error: %v", err))
 				}
+				return nil, err
 			}
-
-		case <-sigChan:
-			fmt.Println("\nStopping watch...")
-			return nil
-		}
+			c.connected()
+			return c.observe(ctx, w, options.ResourceVersion), nil
+		},
 	}
 }
 
-func displayServices(client kubernetes.Interface, namespace string) {
-	services, err := client.CoreV1().Services(namespace).List(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		fmt.Printf("Error listing services: %v\n", err)
-		return
-	}
-
-	table := output.NewTable([]string{"NAME", "TYPE", "CLUSTER-IP", "EXTERNAL-IP", "PORT(S)", "AGE"})
-	for i := range services.Items {
-		service := &services.Items[i]
-		serviceType := string(service.Spec.Type)
-		clusterIP := service.Spec.ClusterIP
-		externalIP := NoneValue
-		if len(service.Spec.ExternalIPs) > 0 {
-			externalIP = service.Spec.ExternalIPs[0]
-		} else if len(service.Status.LoadBalancer.Ingress) > 0 {
-			lb := service.Status.LoadBalancer.Ingress[0]
-			if lb.IP != "" {
-				externalIP = lb.IP
-			} else if lb.Hostname != "" {
-				externalIP = lb.Hostname
-			}
-		}
-
-		ports := []string{}
-		for _, port := range service.Spec.Ports {
-			portStr := fmt.Sprintf("%d/%s", port.Port, port.Protocol)
-			if port.NodePort != 0 {
-				portStr = fmt.Sprintf("%d:%d/%s", port.Port, port.NodePort, port.Protocol)
+// observe passes the events of w through, noting the resourceVersion they
+// carry and how the watch ends.
+func (c *watchConnection) observe(ctx context.Context, w watch.Interface, resourceVersion string) watch.Interface {
+	events := make(chan watch.Event)
+	proxy := watch.NewProxyWatcher(events)
+	go func() {
+		defer close(events)
+		defer w.Stop()
+		for {
+			select {
+			case <-proxy.StopChan():
+				return
+			case event, ok := <-w.ResultChan():
+				if !ok {
+					if ctx.Err() == nil {
+						c.lost("watch closed" + atResourceVersion(resourceVersion))
+					}
+					return
+				}
+				if event.Type == watch.Error {
+					err := apierrors.FromObject(event.Object)
+					if isExpiredWatchError(err) {
+						c.lost("410 Gone" + atResourceVersion(resourceVersion))
+					} else {
+						c.lost(fmt.Sprintf("watch error: %v", err))
+					}
+				} else if accessor, err := meta.Accessor(event.Object); err == nil {
+					resourceVersion = accessor.GetResourceVersion()
+				}
+				select {
+				case events <- event:
+				case <-proxy.StopChan():
+					return
+				}
 			}
-			ports = append(ports, portStr)
 		}
-		portsStr := NoneValue
-		if len(ports) > 0 {
-			portsStr = strings.Join(ports, ",")
-		}
-
-		age := getAge(service.CreationTimestamp)
-
-		table.AddRow([]string{
-			service.Name,
-			serviceType,
-			clusterIP,
-			externalIP,
-			portsStr,
-			age,
-		})
-	}
-	table.Render()
+	}()
+	return proxy
 }
 
-func watchConfigMaps(client kubernetes.Interface, namespace string) error {
-	watcher, err := client.CoreV1().ConfigMaps(namespace).Watch(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		return errors.WrapError(err, "Failed to create watcher")
+func atResourceVersion(resourceVersion string) string {
+	if resourceVersion == "" {
+		return ""
 	}
-	defer watcher.Stop()
-
-	// Handle interrupt signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	// Initial display
-	displayConfigMaps(client, namespace)
+	return " at resourceVersion " + resourceVersion
+}
 
-	fmt.Println("\nWatching for changes... (Ctrl+C to stop)")
+// isExpiredWatchError reports whether a watch failed because the requested
+// resourceVersion is no longer available, so a relist is needed.
+func isExpiredWatchError(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
 
-	// Watch for events
-	for {
+// watchResource keeps an informer cache of the resource and redraws the
+// table from it, debouncing bursts of changes, until ctx is cancelled; with
+// an events output format it prints each change instead. Dropped connections
+// are resumed rather than ending the watch.
+func watchResource(ctx context.Context, client dynamic.Interface, res *resolvedResource, namespace string, table watchTable, opts watchOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	changes := make(chan struct{}, 1)
+	changed := func() {
 		select {
-		case event, ok := <-watcher.ResultChan():
-			if !ok {
-				return errors.WrapError(fmt.Errorf("watch channel closed"), "Watch connection lost")
-			}
-
-			switch event.Type {
-			case watch.Added, watch.Modified, watch.Deleted:
-				// Clear screen and redisplay
-				fmt.Print("\033[2J\033[H")
-				displayConfigMaps(client, namespace)
-				configMap, ok := event.Object.(*corev1.ConfigMap)
-				if ok {
-					fmt.Printf("\nEvent: %s - %s\n", event.Type, configMap.Name)
-				}
-			}
-
-		case <-sigChan:
-			fmt.Println("\nStopping watch...")
-			return nil
+		case changes <- struct{}{}:
+		default:
 		}
 	}
-}
-
-func displayConfigMaps(client kubernetes.Interface, namespace string) {
-	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(context.Background(), metav1.ListOptions{})
+	conn := &watchConnection{changed: changed}
+	informer := cache.NewSharedIndexInformer(
+		conn.listWatch(ctx, client.Resource(res.gvr).Namespace(namespace), opts.listOpts),
+		&unstructured.Unstructured{}, 0, cache.Indexers{},
+	)
+
+	var mu sync.Mutex
+	var lastEvent, lastHook string
+	notify := func(eventType string, obj interface{}) {
+		if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+			mu.Lock()
+			lastEvent = fmt.Sprintf("Event: %s - %s", eventType, key)
+			mu.Unlock()
+		}
+		changed()
+	}
+	handler := cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				notify("ADDED", obj)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) { notify("MODIFIED", obj) },
+		DeleteFunc: func(obj interface{}) { notify("DELETED", obj) },
+	}
+	var events *watchEventPrinter
+	if opts.outputFormat != "" {
+		events = newWatchEventPrinter(table.kind, opts)
+		handler = events.handler()
+	}
+	_, err := informer.AddEventHandler(handler)
 	if err != nil {
-		fmt.Printf("Error listing configmaps: %v\n", err)
-		return
+		return errors.WrapError(err, "Failed to create watcher")
 	}
-
-	table := output.NewTable([]string{"NAME", "DATA", "AGE"})
-	for i := range configMaps.Items {
-		cm := &configMaps.Items[i]
-		dataCount := len(cm.Data)
-		age := getAge(cm.CreationTimestamp)
-
-		table.AddRow([]string{
-			cm.Name,
-			fmt.Sprintf("%d", dataCount),
-			age,
+	if opts.recorder != nil {
+		if _, err := informer.AddEventHandler(opts.recorder.handler()); err != nil {
+			return errors.WrapError(err, "Failed to create watcher")
+		}
+	}
+	if len(opts.hookRules) > 0 {
+		hooks := newWatchHooks(table, opts, func(line string) {
+			if events != nil {
+				events.note(line)
+				return
+			}
+			mu.Lock()
+			lastHook = line
+			mu.Unlock()
+			changed()
 		})
+		defer func() {
+			// No hook starts once the informer has stopped
+			cancel()
+			wg.Wait()
+			hooks.wait()
+		}()
+		if _, err := informer.AddEventHandler(hooks.handler(ctx)); err != nil {
+			return errors.WrapError(err, "Failed to create watcher")
+		}
 	}
-	table.Render()
-}
-
-func watchSecrets(client kubernetes.Interface, namespace string) error {
-	watcher, err := client.CoreV1().Secrets(namespace).Watch(context.Background(), metav1.ListOptions{})
+	err = informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		if ctx.Err() == nil {
+			conn.lost(fmt.Sprintf("connection error: %v", err))
+		}
+	})
 	if err != nil {
 		return errors.WrapError(err, "Failed to create watcher")
 	}
-	defer watcher.Stop()
 
-	// Handle interrupt signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		informer.Run(ctx.Done())
+	}()
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return errors.WrapError(fmt.Errorf("cache did not sync"), "Failed to list "+res.gvr.Resource)
+	}
 
-	// Initial display
-	displaySecrets(client, namespace)
+	if events != nil {
+		for {
+			select {
+			case <-ctx.Done():
+				return recordingError(opts)
+			case <-changes:
+				events.status(conn.line())
+			}
+		}
+	}
 
-	fmt.Println("\nWatching for changes... (Ctrl+C to stop)")
+	screen := output.NewScreen(opts.writer())
+	draw := func() {
+		mu.Lock()
+		event, hook := lastEvent, lastHook
+		mu.Unlock()
+		screen.Draw(table.render(informer.GetStore().List(), opts.columns, event, hook, conn.line()))
+	}
+	draw()
 
-	// Watch for events
+	refresh := time.NewTicker(watchRefresh)
+	defer refresh.Stop()
+	var debounce <-chan time.Time
 	for {
 		select {
-		case event, ok := <-watcher.ResultChan():
-			if !ok {
-				return errors.WrapError(fmt.Errorf("watch channel closed"), "Watch connection lost")
+		case <-ctx.Done():
+			_, _ = fmt.Fprintln(opts.writer(), "\nStopping watch...")
+			return recordingError(opts)
+		case <-changes:
+			if debounce == nil {
+				debounce = time.After(opts.debounce)
 			}
+		case <-debounce:
+			debounce = nil
+			draw()
+		case <-refresh.C:
+			draw()
+		}
+	}
+}
 
-			switch event.Type {
-			case watch.Added, watch.Modified, watch.Deleted:
-				// Clear screen and redisplay
-				fmt.Print("\033[2J\033[H")
-				displaySecrets(client, namespace)
-				secret, ok := event.Object.(*corev1.Secret)
-				if ok {
-					fmt.Printf("\nEvent: %s - %s\n", event.Type, secret.Name)
-				}
-			}
+// recordingError reports a recording that could not be written completely.
+func recordingError(opts watchOptions) error {
+	if opts.recorder == nil {
+		return nil
+	}
+	if err := opts.recorder.err(); err != nil {
+		return errors.WrapError(err, fmt.Sprintf("Failed to write recording %s", opts.record))
+	}
+	return nil
+}
 
-		case <-sigChan:
-			fmt.Println("\nStopping watch...")
-			return nil
+// render lays out the cached objects, sorted by namespace and name, as the
+// lines of the screen, followed by the non-empty footer lines such as the
+// last event.
+func (t watchTable) render(objects []interface{}, columns columnOptions, footer ...string) []string {
+	lines := t.renderTable(objects, columns)
+	lines = append(lines, "", "Watching for changes... (Ctrl+C to stop)")
+	for _, line := range footer {
+		if line != "" {
+			lines = append(lines, line)
 		}
 	}
+	return lines
 }
 
-func displaySecrets(client kubernetes.Interface, namespace string) {
-	secrets, err := client.CoreV1().Secrets(namespace).List(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		fmt.Printf("Error listing secrets: %v\n", err)
-		return
+// renderTable lays out the objects, sorted by namespace and name, as table
+// lines.
+func (t watchTable) renderTable(objects []interface{}, columns columnOptions) []string {
+	items := make([]*unstructured.Unstructured, 0, len(objects))
+	for _, obj := range objects {
+		if item, ok := obj.(*unstructured.Unstructured); ok {
+			items = append(items, item)
+		}
 	}
-
-	table := output.NewTable([]string{"NAME", "TYPE", "DATA", "AGE"})
-	for i := range secrets.Items {
-		secret := &secrets.Items[i]
-		secretType := string(secret.Type)
-		if secretType == "" {
-			secretType = SecretTypeOpaque
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
 		}
-		dataCount := len(secret.Data)
-		age := getAge(secret.CreationTimestamp)
-
-		table.AddRow([]string{
-			secret.Name,
-			secretType,
-			fmt.Sprintf("%d", dataCount),
-			age,
-		})
+		return items[i].GetName() < items[j].GetName()
+	})
+
+	table := output.NewTable(columns.headers(t.headers))
+	for _, item := range items {
+		row := t.row(item)
+		if t.now != nil {
+			for i, header := range t.headers {
+				if header == "AGE" && i < len(row) {
+					row[i] = ageAt(item.GetCreationTimestamp(), t.now())
+				}
+			}
+		}
+		table.AddRow(columns.row(t.kind, item, row))
 	}
-	table.Render()
+	var buf bytes.Buffer
+	table.RenderTo(&buf)
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
 }
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// The row functions below give watch the columns kubectl prints for types
+// that get leaves to the server.
+
+func statefulSetWatchRow(statefulSet *appsv1.StatefulSet) []string {
+	return []string{
+		statefulSet.Name,
+		fmt.Sprintf("%d/%d", statefulSet.Status.ReadyReplicas, getDesiredReplicas(statefulSet.Spec.Replicas)),
+		getAge(statefulSet.CreationTimestamp),
+	}
+}
+
+func daemonSetWatchRow(daemonSet *appsv1.DaemonSet) []string {
+	return []string{
+		daemonSet.Name,
+		fmt.Sprintf("%d", daemonSet.Status.DesiredNumberScheduled),
+		fmt.Sprintf("%d", daemonSet.Status.CurrentNumberScheduled),
+		fmt.Sprintf("%d", daemonSet.Status.NumberReady),
+		fmt.Sprintf("%d", daemonSet.Status.UpdatedNumberScheduled),
+		fmt.Sprintf("%d", daemonSet.Status.NumberAvailable),
+		labels.FormatLabels(daemonSet.Spec.Template.Spec.NodeSelector),
+		getAge(daemonSet.CreationTimestamp),
+	}
+}
+
+func replicaSetWatchRow(replicaSet *appsv1.ReplicaSet) []string {
+	return []string{
+		replicaSet.Name,
+		fmt.Sprintf("%d", getDesiredReplicas(replicaSet.Spec.Replicas)),
+		fmt.Sprintf("%d", replicaSet.Status.Replicas),
+		fmt.Sprintf("%d", replicaSet.Status.ReadyReplicas),
+		getAge(replicaSet.CreationTimestamp),
+	}
+}
+
+func jobWatchRow(job *batchv1.Job) []string {
+	// Like kubectl, a job without completions counts towards one success
+	completions := fmt.Sprintf("%d/1", job.Status.Succeeded)
+	if job.Spec.Completions != nil {
+		completions = fmt.Sprintf("%d/%d", job.Status.Succeeded, *job.Spec.Completions)
+	} else if job.Spec.Parallelism != nil && *job.Spec.Parallelism > 1 {
+		completions = fmt.Sprintf("%d/1 of %d", job.Status.Succeeded, *job.Spec.Parallelism)
+	}
+
+	jobDuration := ""
+	if start := job.Status.StartTime; start != nil {
+		end := time.Now()
+		if job.Status.CompletionTime != nil {
+			end = job.Status.CompletionTime.Time
+		}
+		jobDuration = duration.HumanDuration(end.Sub(start.Time))
+	}
+
+	return []string{
+		job.Name,
+		completions,
+		jobDuration,
+		getAge(job.CreationTimestamp),
+	}
+}
+
+func cronJobWatchRow(cronJob *batchv1.CronJob) []string {
+	suspend := "False"
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		suspend = "True"
+	}
+	lastSchedule := NoneValue
+	if cronJob.Status.LastScheduleTime != nil {
+		lastSchedule = getAge(*cronJob.Status.LastScheduleTime)
+	}
+	return []string{
+		cronJob.Name,
+		cronJob.Spec.Schedule,
+		valueOrNone(stringValue(cronJob.Spec.TimeZone)),
+		suspend,
+		fmt.Sprintf("%d", len(cronJob.Status.Active)),
+		lastSchedule,
+		getAge(cronJob.CreationTimestamp),
+	}
+}
+
+func claimWatchRow(claim *corev1.PersistentVolumeClaim) []string {
+	capacity, accessModes := "", ""
+	if claim.Spec.VolumeName != "" {
+		if storage, ok := claim.Status.Capacity[corev1.ResourceStorage]; ok {
+			capacity = storage.String()
+		}
+		accessModes = formatAccessModes(claim.Status.AccessModes)
+	}
+	return []string{
+		claim.Name,
+		string(claim.Status.Phase),
+		claim.Spec.VolumeName,
+		capacity,
+		accessModes,
+		stringValue(claim.Spec.StorageClassName),
+		getAge(claim.CreationTimestamp),
+	}
+}
+
+func volumeWatchRow(volume *corev1.PersistentVolume) []string {
+	capacity := ""
+	if storage, ok := volume.Spec.Capacity[corev1.ResourceStorage]; ok {
+		capacity = storage.String()
+	}
+	claim := ""
+	if ref := volume.Spec.ClaimRef; ref != nil {
+		claim = ref.Namespace + "/" + ref.Name
+	}
+	return []string{
+		volume.Name,
+		capacity,
+		formatAccessModes(volume.Spec.AccessModes),
+		string(volume.Spec.PersistentVolumeReclaimPolicy),
+		string(volume.Status.Phase),
+		claim,
+		volume.Spec.StorageClassName,
+		volume.Status.Reason,
+		getAge(volume.CreationTimestamp),
+	}
+}
+
+func autoscalerWatchRow(hpa *autoscalingv2.HorizontalPodAutoscaler) []string {
+	targets := make([]string, 0, len(hpa.Spec.Metrics))
+	for i, metric := range hpa.Spec.Metrics {
+		var current *autoscalingv2.MetricStatus
+		if i < len(hpa.Status.CurrentMetrics) {
+			current = &hpa.Status.CurrentMetrics[i]
+		}
+		_, value := formatHPAMetric(metric, current)
+		targets = append(targets, value)
+	}
+	minReplicas := "<unset>"
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = fmt.Sprintf("%d", *hpa.Spec.MinReplicas)
+	}
+	return []string{
+		hpa.Name,
+		hpa.Spec.ScaleTargetRef.Kind + "/" + hpa.Spec.ScaleTargetRef.Name,
+		valueOrNone(strings.Join(targets, ", ")),
+		minReplicas,
+		fmt.Sprintf("%d", hpa.Spec.MaxReplicas),
+		fmt.Sprintf("%d", hpa.Status.CurrentReplicas),
+		getAge(hpa.CreationTimestamp),
+	}
+}
@@ -0,0 +1,358 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+// watchMaxChanges is how many changed fields an event line lists before
+// summarizing the rest
+const watchMaxChanges = 5
+
+// watchIgnoredFields change on every write or only track bookkeeping, so
+// they are left out of change summaries.
+var watchIgnoredFields = map[string]bool{
+	"resourceVersion":    true,
+	"managedFields":      true,
+	"generation":         true,
+	"observedGeneration": true,
+	"lastTransitionTime": true,
+	"lastProbeTime":      true,
+	"lastHeartbeatTime":  true,
+	"lastUpdateTime":     true,
+	"containerID":        true,
+	"imageID":            true,
+}
+
+// watchFieldAliases shorten well-known field paths in change summaries.
+var watchFieldAliases = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`^status\.containerStatuses\[([^\]]+)\]\.restartCount$`), "restarts[$1]"},
+	{regexp.MustCompile(`^status\.initContainerStatuses\[([^\]]+)\]\.restartCount$`), "init-restarts[$1]"},
+}
+
+// fieldChange is one field that differs between two versions of an object;
+// Old or New is nil when the field is absent.
+type fieldChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// watchEventRecord is the JSON form of a watch event.
+type watchEventRecord struct {
+	Time      string        `json:"time"`
+	Type      string        `json:"type"`
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	Changes   []fieldChange `json:"changes,omitempty"`
+}
+
+// watchEventPrinter prints one line, or JSON record, per change of a watched
+// object, summarizing the fields that changed since its previous version.
+type watchEventPrinter struct {
+	kind string
+	json bool
+	// showSecrets keeps secret values in change summaries
+	showSecrets bool
+	out         io.Writer
+	now         func() time.Time
+
+	// mu keeps event and status lines from interleaving
+	mu         sync.Mutex
+	lastStatus string
+}
+
+func newWatchEventPrinter(kind string, opts watchOptions) *watchEventPrinter {
+	return &watchEventPrinter{
+		kind:        kind,
+		json:        opts.outputFormat == OutputFormatJSONL,
+		showSecrets: opts.showSecrets,
+		out:         opts.writer(),
+		now:         time.Now,
+	}
+}
+
+// handler prints the events after the initial list.
+func (p *watchEventPrinter) handler() cache.ResourceEventHandlerDetailedFuncs {
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				p.print("ADDED", nil, obj)
+			}
+		},
+		UpdateFunc: func(old, obj interface{}) { p.print("MODIFIED", old, obj) },
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			p.print("DELETED", nil, obj)
+		},
+	}
+}
+
+// print writes one event; updates that change nothing of interest, such as
+// those replayed by a relist, are skipped.
+func (p *watchEventPrinter) print(eventType string, old, obj interface{}) {
+	item, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	record := watchEventRecord{
+		Time:      p.now().Format(time.RFC3339),
+		Type:      eventType,
+		Kind:      p.kind,
+		Namespace: item.GetNamespace(),
+		Name:      item.GetName(),
+	}
+	if previous, ok := old.(*unstructured.Unstructured); ok {
+		diffFields("", previous.Object, item.Object, &record.Changes)
+		if len(record.Changes) == 0 {
+			return
+		}
+		if isSecretObject(item.Object) && !p.showSecrets {
+			redactSecretChanges(record.Changes)
+		}
+	}
+
+	line := p.text(record)
+	if p.json {
+		line = marshalWatchEvent(record)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, _ = fmt.Fprintln(p.out, line)
+}
+
+// status prints the connection status when it changes; JSON output leaves
+// it out so that every line is an event.
+func (p *watchEventPrinter) status(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.json || line == "" || line == p.lastStatus {
+		return
+	}
+	p.lastStatus = line
+	_, _ = fmt.Fprintln(p.out, line)
+}
+
+// note prints a line about the watch itself, such as a hook outcome; JSON
+// output leaves it out.
+func (p *watchEventPrinter) note(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.json {
+		_, _ = fmt.Fprintln(p.out, line)
+	}
+}
+
+func (p *watchEventPrinter) text(record watchEventRecord) string {
+	eventColor := output.Info
+	switch record.Type {
+	case "ADDED":
+		eventColor = output.Success
+	case "DELETED":
+		eventColor = output.Error
+	}
+	when, _ := time.Parse(time.RFC3339, record.Time)
+	parts := []string{
+		output.Dim.Sprint(when.Format("15:04:05")),
+		eventColor.Sprintf("%-8s", record.Type),
+		record.Kind + "/" + record.Name,
+	}
+
+	changes := make([]string, 0, len(record.Changes))
+	for i, change := range record.Changes {
+		if i == watchMaxChanges {
+			changes = append(changes, output.Dim.Sprintf("(+%d more)", len(record.Changes)-i))
+			break
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s → %s",
+			change.Path, formatFieldValue(change.Old), formatFieldValue(change.New)))
+	}
+	if len(changes) > 0 {
+		parts = append(parts, strings.Join(changes, ", "))
+	}
+	return strings.Join(parts, " ")
+}
+
+// diffFields appends the leaf fields that differ between old and new to
+// changes. Lists of named items, such as containers, or of typed items, such
+// as conditions, are matched by that key rather than by position.
+func diffFields(path string, old, new interface{}, changes *[]fieldChange) {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	oldList, oldIsList := old.([]interface{})
+	newList, newIsList := new.([]interface{})
+
+	switch {
+	case (oldIsMap || old == nil) && (newIsMap || new == nil) && (oldIsMap || newIsMap):
+		keys := make([]string, 0, len(oldMap)+len(newMap))
+		for key := range oldMap {
+			keys = append(keys, key)
+		}
+		for key := range newMap {
+			if _, ok := oldMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !watchIgnoredFields[key] {
+				diffFields(joinFieldPath(path, key), oldMap[key], newMap[key], changes)
+			}
+		}
+	case (oldIsList || old == nil) && (newIsList || new == nil) && (oldIsList || newIsList):
+		if key := listItemKey(oldList, newList); key != "" {
+			oldItems, order := keyedItems(oldList, key, nil)
+			newItems, order := keyedItems(newList, key, order)
+			for _, name := range order {
+				diffFields(fmt.Sprintf("%s[%s]", path, name), oldItems[name], newItems[name], changes)
+			}
+			return
+		}
+		if len(oldList) == len(newList) {
+			for i := range oldList {
+				diffFields(fmt.Sprintf("%s[%d]", path, i), oldList[i], newList[i], changes)
+			}
+			return
+		}
+		*changes = append(*changes, fieldChange{Path: aliasFieldPath(path), Old: old, New: new})
+	default:
+		if !reflect.DeepEqual(old, new) {
+			*changes = append(*changes, fieldChange{Path: aliasFieldPath(path), Old: old, New: new})
+		}
+	}
+}
+
+// redactSecretChanges replaces the old and new values of changed secret
+// data, and of the last-applied annotation that repeats it, by the redaction
+// marker; added or removed keys still show as such.
+func redactSecretChanges(changes []fieldChange) {
+	for i := range changes {
+		change := &changes[i]
+		redact := redactedValue
+		switch {
+		case strings.HasPrefix(change.Path, "data."):
+		case strings.HasPrefix(change.Path, "stringData."),
+			change.Path == "metadata.annotations."+corev1.LastAppliedConfigAnnotation:
+			// These hold plain text rather than base64
+			redact = func(value interface{}) string { return redactedMarker(len(fmt.Sprint(value))) }
+		default:
+			continue
+		}
+		if change.Old != nil {
+			change.Old = redact(change.Old)
+		}
+		if change.New != nil {
+			change.New = redact(change.New)
+		}
+	}
+}
+
+// listItemKey returns the field that identifies the items of both lists:
+// "name" or "type", or "" when the items are positional.
+func listItemKey(lists ...[]interface{}) string {
+	for _, key := range []string{"name", "type"} {
+		keyed := true
+		for _, list := range lists {
+			for _, item := range list {
+				fields, ok := item.(map[string]interface{})
+				if !ok {
+					keyed = false
+					break
+				}
+				if _, ok := fields[key].(string); !ok {
+					keyed = false
+					break
+				}
+			}
+		}
+		if keyed {
+			return key
+		}
+	}
+	return ""
+}
+
+// keyedItems indexes list by key, appending names not seen yet to order.
+func keyedItems(list []interface{}, key string, order []string) (map[string]interface{}, []string) {
+	items := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		name := item.(map[string]interface{})[key].(string)
+		items[name] = item
+		if !containsString(order, name) {
+			order = append(order, name)
+		}
+	}
+	return items, order
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func aliasFieldPath(path string) string {
+	for _, alias := range watchFieldAliases {
+		if alias.pattern.MatchString(path) {
+			return alias.pattern.ReplaceAllString(path, alias.replacement)
+		}
+	}
+	return path
+}
+
+// formatFieldValue renders a field value for an event line; strings are
+// colored by status so state transitions stand out.
+func formatFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return NoneValue
+	case string:
+		return output.ColorizeStatus(v)
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func marshalWatchEvent(record watchEventRecord) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(record); err != nil {
+		return fmt.Sprintf(`{"type":%q,"name":%q}`, record.Type, record.Name)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
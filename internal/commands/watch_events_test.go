@@ -0,0 +1,223 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestDiffFields(t *testing.T) {
+	old := testLogPod("api-0", map[string]string{"app": "api"}, "app", "sidecar")
+	old.ResourceVersion = "1"
+	old.Status.Phase = corev1.PodPending
+	old.Status.ContainerStatuses[0].RestartCount = 3
+	old.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+		{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.Unix(1, 0)},
+	}
+
+	updated := old.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Labels["version"] = "v2"
+	updated.Status.Phase = corev1.PodRunning
+	updated.Status.ContainerStatuses[0].RestartCount = 4
+	updated.Status.Conditions[1].Status = corev1.ConditionTrue
+	updated.Status.Conditions[1].LastTransitionTime = metav1.Unix(2, 0)
+
+	var changes []fieldChange
+	diffFields("", toUnstructuredMap(t, old), toUnstructuredMap(t, updated), &changes)
+
+	var paths []string
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	expected := []string{"metadata.labels.version", "status.conditions[Ready].status", "restarts[app]", "status.phase"}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected changes %v, got %v", expected, paths)
+	}
+	if changes[0].Old != nil || changes[0].New != "v2" {
+		t.Errorf("Expected an added label, got %+v", changes[0])
+	}
+	if changes[2].Old != int64(3) || changes[2].New != int64(4) {
+		t.Errorf("Expected restarts 3 → 4, got %+v", changes[2])
+	}
+}
+
+func toUnstructuredMap(t *testing.T, obj interface{}) map[string]interface{} {
+	t.Helper()
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("failed to convert object: %v", err)
+	}
+	return content
+}
+
+func TestWatchEventPrinter(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	old := unstructuredPod(t, "api-0", corev1.PodPending)
+	updated := unstructuredPod(t, "api-0", corev1.PodRunning)
+	updated.SetResourceVersion("2")
+	now := func() time.Time { return time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC) }
+
+	var out bytes.Buffer
+	printer := newWatchEventPrinter("pod", watchOptions{outputFormat: OutputFormatEvents, out: &out})
+	printer.now = now
+	printer.print("MODIFIED", old, updated)
+	// A relist replays objects that did not change
+	printer.print("MODIFIED", updated, updated.DeepCopy())
+	printer.print("DELETED", nil, updated)
+	printer.status("Reconnected at 15:04:05 after watch closed, 1 reconnect so far")
+	printer.status("Reconnected at 15:04:05 after watch closed, 1 reconnect so far")
+
+	expected := "15:04:05 MODIFIED pod/api-0 status.phase: Pending → Running\n" +
+		"15:04:05 DELETED  pod/api-0\n" +
+		"Reconnected at 15:04:05 after watch closed, 1 reconnect so far\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	printer = newWatchEventPrinter("pod", watchOptions{outputFormat: OutputFormatJSONL, out: &out})
+	printer.now = now
+	printer.print("MODIFIED", old, updated)
+	printer.status("Reconnected at 15:04:05 after watch closed, 1 reconnect so far")
+
+	var record watchEventRecord
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a single JSON record, got %q: %v", out.String(), err)
+	}
+	if record.Time != "2024-01-02T15:04:05Z" || record.Type != "MODIFIED" || record.Kind != "pod" ||
+		record.Namespace != "default" || record.Name != "api-0" {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	if len(record.Changes) != 1 || record.Changes[0] != (fieldChange{Path: "status.phase", Old: "Pending", New: "Running"}) {
+		t.Errorf("Unexpected changes: %+v", record.Changes)
+	}
+}
+
+func TestWatchEventPrinterRedactsSecrets(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	// Secrets created by kubectl apply repeat their data in an annotation
+	secret := func(password string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{"name": "db", "namespace": "default", "annotations": map[string]interface{}{
+				corev1.LastAppliedConfigAnnotation: `{"data":{"password":"` + password + `"}}`,
+			}},
+			"data": map[string]interface{}{"password": password},
+		}}
+	}
+	// "hunter2" and "hunter3"
+	old, updated := secret("aHVudGVyMg=="), secret("aHVudGVyMw==")
+	now := func() time.Time { return time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC) }
+
+	var out bytes.Buffer
+	printer := newWatchEventPrinter("secret", watchOptions{outputFormat: OutputFormatEvents, out: &out})
+	printer.now = now
+	printer.print("MODIFIED", old, updated)
+	expected := "15:04:05 MODIFIED secret/db data.password: <redacted: 7 bytes> → <redacted: 7 bytes>, " +
+		"metadata.annotations." + corev1.LastAppliedConfigAnnotation + ": <redacted: 36 bytes> → <redacted: 36 bytes>\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	printer = newWatchEventPrinter("secret", watchOptions{outputFormat: OutputFormatJSONL, out: &out})
+	printer.print("MODIFIED", old, updated)
+	if strings.Contains(out.String(), "aHVudGVy") {
+		t.Errorf("Expected secret values to be redacted, got %s", out.String())
+	}
+
+	out.Reset()
+	printer = newWatchEventPrinter("secret", watchOptions{outputFormat: OutputFormatJSONL, out: &out, showSecrets: true})
+	printer.print("MODIFIED", old, updated)
+	var record watchEventRecord
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a single JSON record, got %q: %v", out.String(), err)
+	}
+	if len(record.Changes) != 2 || record.Changes[0] != (fieldChange{Path: "data.password", Old: "aHVudGVyMg==", New: "aHVudGVyMw=="}) {
+		t.Errorf("Expected --show-secrets to keep the values, got %+v", record.Changes)
+	}
+}
+
+func TestFormatFieldValueColorsStates(t *testing.T) {
+	if !output.IsColorEnabled() {
+		output.EnableColors()
+		defer output.DisableColors()
+	}
+
+	if result := formatFieldValue("CrashLoopBackOff"); result != output.ColorizeStatus("CrashLoopBackOff") || result == "CrashLoopBackOff" {
+		t.Errorf("Expected a colored state, got %q", result)
+	}
+	if result := formatFieldValue(nil); result != NoneValue {
+		t.Errorf("Expected %s for an absent field, got %q", NoneValue, result)
+	}
+	if result := formatFieldValue(map[string]interface{}{"reason": "OOMKilled"}); result != `{"reason":"OOMKilled"}` {
+		t.Errorf("Expected compact JSON, got %q", result)
+	}
+}
+
+func TestWatchResourceEvents(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podResource: "PodList"},
+		unstructuredPod(t, "api-0", corev1.PodPending),
+	)
+	res := &resolvedResource{gvr: podResource, kind: "Pod", namespaced: true}
+
+	var out syncBuffer
+	opts := watchOptions{outputFormat: OutputFormatEvents, out: &out}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watchResource(ctx, client, res, "default", newWatchTable(client, res), opts)
+	}()
+
+	// Updates may race the initial list, so keep flipping the phase until
+	// the transition is seen
+	deadline := time.Now().Add(5 * time.Second)
+	phases := []corev1.PodPhase{corev1.PodRunning, corev1.PodPending}
+	for i := 0; !strings.Contains(out.String(), "MODIFIED pod/api-0 status.phase: Pending → Running"); i++ {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for the update, got:\n%s", out.String())
+		}
+		pod := unstructuredPod(t, "api-0", phases[i%2])
+		if _, err := client.Resource(podResource).Namespace("default").Update(context.Background(), pod, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("failed to update pod: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watchResource failed: %v", err)
+	}
+	if strings.Contains(out.String(), "RESTARTS") || strings.Contains(out.String(), "\033[") {
+		t.Errorf("Expected plain event lines without the table, got:\n%s", out.String())
+	}
+}
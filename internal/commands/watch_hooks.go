@@ -0,0 +1,459 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// watchHookCooldown is how long a rule stays quiet for an object after
+	// it fired
+	watchHookCooldown = 5 * time.Minute
+	// watchHookRate is how many hooks may run per minute
+	watchHookRate = 10
+	// watchHookTimeout bounds a single command or webhook call
+	watchHookTimeout = 30 * time.Second
+)
+
+// watchRuleOperators are the comparisons a rule condition may use, longest
+// first so that "!=" is not read as "=".
+var watchRuleOperators = []string{"!=", ">=", "<=", "==", "=", ">", "<"}
+
+// watchRule is a set of conditions that must all hold for an event to
+// match, e.g. "type=MODIFIED,status=CrashLoopBackOff".
+type watchRule struct {
+	text       string
+	conditions []watchCondition
+}
+
+// watchCondition compares one field of an event: "type", a column of the
+// watch table such as "status" or "restarts", or a dotted object path such
+// as "status.phase" or "metadata.labels.app". Keys that contain dots are
+// written in brackets or with escaped dots, e.g.
+// "metadata.labels[app.kubernetes.io/name]".
+type watchCondition struct {
+	field string
+	op    string
+	value string
+}
+
+// parseWatchRule parses comma separated field comparisons.
+func parseWatchRule(text string) (watchRule, error) {
+	rule := watchRule{text: text}
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		index := strings.IndexAny(part, "!=<>")
+		if index <= 0 {
+			return rule, fmt.Errorf("invalid rule '%s': expected field=value conditions, e.g. status=CrashLoopBackOff", text)
+		}
+		var op string
+		for _, candidate := range watchRuleOperators {
+			if strings.HasPrefix(part[index:], candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return rule, fmt.Errorf("invalid rule '%s': unknown operator in '%s'", text, part)
+		}
+		condition := watchCondition{
+			field: strings.TrimSpace(part[:index]),
+			op:    op,
+			value: strings.TrimSpace(part[index+len(op):]),
+		}
+		if condition.op == "==" {
+			condition.op = "="
+		}
+		if strings.ContainsAny(condition.value, "!=<>") {
+			return rule, fmt.Errorf("invalid rule '%s': unexpected operator in '%s'", text, part)
+		}
+		if _, err := splitFieldPath(condition.field); err != nil {
+			return rule, fmt.Errorf("invalid rule '%s': %v", text, err)
+		}
+		rule.conditions = append(rule.conditions, condition)
+	}
+	return rule, nil
+}
+
+// matches reports whether the event satisfies every condition.
+func (r watchRule) matches(eventType string, obj *unstructured.Unstructured, table watchTable) bool {
+	var row []string
+	for _, condition := range r.conditions {
+		var value string
+		switch field := strings.ToLower(condition.field); {
+		case field == "type":
+			value = eventType
+		case columnIndex(table.headers, field) >= 0:
+			if row == nil {
+				row = table.row(obj)
+			}
+			if i := columnIndex(table.headers, field); i < len(row) {
+				value = row[i]
+			}
+		default:
+			path, _ := splitFieldPath(condition.field)
+			found, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, path...)
+			if ok && found != nil {
+				value = fmt.Sprintf("%v", found)
+			}
+		}
+		if !condition.holds(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitFieldPath splits a dotted object path into its keys. A key may be
+// written in brackets, optionally quoted, or with its dots escaped by a
+// backslash: "metadata.labels[app.kubernetes.io/name]" and
+// "metadata.labels.app\.kubernetes\.io/name" name the same label.
+func splitFieldPath(path string) ([]string, error) {
+	var keys []string
+	var key strings.Builder
+	// closed is set right after a bracketed key, which must be followed by
+	// a dot, another bracket or the end of the path
+	closed := false
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '.':
+			if key.Len() == 0 && !closed {
+				return nil, fmt.Errorf("empty key in field '%s'", path)
+			}
+			if !closed {
+				keys = append(keys, key.String())
+			}
+			key.Reset()
+			closed = false
+		case c == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ']' in field '%s'", path)
+			}
+			if key.Len() > 0 {
+				keys = append(keys, key.String())
+				key.Reset()
+			}
+			bracketed := path[i+1 : i+end]
+			if len(bracketed) >= 2 && (bracketed[0] == '\'' || bracketed[0] == '"') && bracketed[len(bracketed)-1] == bracketed[0] {
+				bracketed = bracketed[1 : len(bracketed)-1]
+			}
+			if bracketed == "" {
+				return nil, fmt.Errorf("empty key in field '%s'", path)
+			}
+			keys = append(keys, bracketed)
+			i += end
+			closed = true
+		case closed:
+			return nil, fmt.Errorf("expected '.' or '[' after ']' in field '%s'", path)
+		case c == '\\':
+			if i+1 == len(path) {
+				return nil, fmt.Errorf("trailing '\\' in field '%s'", path)
+			}
+			i++
+			key.WriteByte(path[i])
+		default:
+			key.WriteByte(c)
+		}
+	}
+	if !closed {
+		if key.Len() == 0 {
+			return nil, fmt.Errorf("empty key in field '%s'", path)
+		}
+		keys = append(keys, key.String())
+	}
+	return keys, nil
+}
+
+func columnIndex(headers []string, field string) int {
+	for i, header := range headers {
+		if strings.ToLower(header) == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// holds compares value, ignoring case; ordering operators compare numbers.
+func (c watchCondition) holds(value string) bool {
+	switch c.op {
+	case "=":
+		return strings.EqualFold(value, c.value)
+	case "!=":
+		return !strings.EqualFold(value, c.value)
+	}
+	actual, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	expected, err := strconv.ParseFloat(c.value, 64)
+	if err != nil {
+		return false
+	}
+	switch c.op {
+	case ">":
+		return actual > expected
+	case ">=":
+		return actual >= expected
+	case "<":
+		return actual < expected
+	case "<=":
+		return actual <= expected
+	}
+	return false
+}
+
+// watchHookEvent is a rule firing for an object; it is also the webhook
+// payload.
+type watchHookEvent struct {
+	Time      string                 `json:"time"`
+	Type      string                 `json:"type"`
+	Rule      string                 `json:"rule"`
+	Kind      string                 `json:"kind"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Name      string                 `json:"name"`
+	Object    map[string]interface{} `json:"object"`
+}
+
+// watchHooks runs commands and webhooks when watched objects start matching
+// a rule. A rule fires at most once per object within the cooldown, and all
+// hooks together are rate limited.
+type watchHooks struct {
+	rules    []watchRule
+	table    watchTable
+	actions  []watchHookAction
+	cooldown time.Duration
+	rate     int
+	limiter  flowcontrol.RateLimiter
+	now      func() time.Time
+	// showSecrets passes secret values to the hooks instead of redacting them
+	showSecrets bool
+	// report receives the outcome of every hook
+	report func(string)
+
+	mu    sync.Mutex
+	fired map[string]time.Time
+	wg    sync.WaitGroup
+}
+
+// watchHookAction is something a firing rule triggers.
+type watchHookAction struct {
+	name string
+	run  func(ctx context.Context, event watchHookEvent) error
+}
+
+// completeHooks parses the --on rules and checks they have something to run.
+func (o *watchOptions) completeHooks() error {
+	if len(o.hookOn) == 0 {
+		if o.hookExec != "" || o.hookWebhook != "" {
+			return fmt.Errorf("--exec and --webhook need at least one --on rule")
+		}
+		return nil
+	}
+	if o.hookExec == "" && o.hookWebhook == "" {
+		return fmt.Errorf("--on needs --exec or --webhook to run when it matches")
+	}
+	if o.hookWebhook != "" {
+		if parsed, err := url.Parse(o.hookWebhook); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid --webhook '%s', expected an http or https URL", o.hookWebhook)
+		}
+	}
+	if o.hookRate < 1 {
+		return fmt.Errorf("--hook-rate must be at least 1")
+	}
+	if o.hookCooldown < 0 {
+		return fmt.Errorf("--hook-cooldown must not be negative")
+	}
+	o.hookRules = nil
+	for _, text := range o.hookOn {
+		rule, err := parseWatchRule(text)
+		if err != nil {
+			return err
+		}
+		o.hookRules = append(o.hookRules, rule)
+	}
+	return nil
+}
+
+func newWatchHooks(table watchTable, opts watchOptions, report func(string)) *watchHooks {
+	h := &watchHooks{
+		rules:       opts.hookRules,
+		table:       table,
+		cooldown:    opts.hookCooldown,
+		rate:        opts.hookRate,
+		limiter:     flowcontrol.NewTokenBucketRateLimiter(float32(opts.hookRate)/60, opts.hookRate),
+		now:         time.Now,
+		report:      report,
+		fired:       map[string]time.Time{},
+		showSecrets: opts.showSecrets,
+	}
+	if opts.hookExec != "" {
+		h.actions = append(h.actions, watchHookAction{name: "exec", run: execHook(opts.hookExec)})
+	}
+	if opts.hookWebhook != "" {
+		h.actions = append(h.actions, watchHookAction{name: "webhook", run: webhookHook(opts.hookWebhook)})
+	}
+	return h
+}
+
+// handler checks every change after the initial list, which is the state
+// transitions are measured from.
+func (h *watchHooks) handler(ctx context.Context) cache.ResourceEventHandlerDetailedFuncs {
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				h.check(ctx, "ADDED", nil, obj)
+			}
+		},
+		UpdateFunc: func(old, obj interface{}) { h.check(ctx, "MODIFIED", old, obj) },
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			h.check(ctx, "DELETED", obj, obj)
+		},
+	}
+}
+
+// check fires the rules the object has just started to match: ones the
+// previous version of the object, if any, did not match already.
+func (h *watchHooks) check(ctx context.Context, eventType string, old, obj interface{}) {
+	item, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	previous, _ := old.(*unstructured.Unstructured)
+	for _, rule := range h.rules {
+		if !rule.matches(eventType, item, h.table) {
+			continue
+		}
+		// The previous version was present, so a deletion fires rules on
+		// the event type but not field rules that already held
+		if previous != nil && rule.matches("MODIFIED", previous, h.table) {
+			continue
+		}
+		h.fire(ctx, eventType, rule, item)
+	}
+}
+
+// fire runs the actions for a matching event unless the rule fired for the
+// object within the cooldown or the rate limit is reached.
+func (h *watchHooks) fire(ctx context.Context, eventType string, rule watchRule, obj *unstructured.Unstructured) {
+	key := rule.text + "|" + obj.GetNamespace() + "/" + obj.GetName()
+	now := h.now()
+	h.mu.Lock()
+	if last, ok := h.fired[key]; ok && now.Sub(last) < h.cooldown {
+		h.mu.Unlock()
+		return
+	}
+	if !h.limiter.TryAccept() {
+		h.mu.Unlock()
+		h.report(fmt.Sprintf("Hook for %s/%s (%s) skipped: more than %d hooks per minute", h.table.kind, obj.GetName(), rule.text, h.rate))
+		return
+	}
+	h.fired[key] = now
+	h.mu.Unlock()
+
+	object := obj.DeepCopy().Object
+	if !h.showSecrets {
+		_ = redactSecretObject(object, false)
+	}
+	event := watchHookEvent{
+		Time:      now.Format(time.RFC3339),
+		Type:      eventType,
+		Rule:      rule.text,
+		Kind:      h.table.kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Object:    object,
+	}
+	for _, action := range h.actions {
+		h.wg.Add(1)
+		go func(action watchHookAction) {
+			defer h.wg.Done()
+			runCtx, cancel := context.WithTimeout(ctx, watchHookTimeout)
+			defer cancel()
+			target := fmt.Sprintf("%s/%s", event.Kind, event.Name)
+			if err := action.run(runCtx, event); err != nil {
+				h.report(fmt.Sprintf("Hook %s for %s (%s) failed: %v", action.name, target, event.Rule, err))
+				return
+			}
+			h.report(fmt.Sprintf("Hook %s for %s (%s) ran at %s", action.name, target, event.Rule, now.Format("15:04:05")))
+		}(action)
+	}
+}
+
+// wait blocks until the running hooks finish.
+func (h *watchHooks) wait() {
+	h.wg.Wait()
+}
+
+// execHook runs command in the shell with the object as JSON on stdin and
+// the event in K8CTL_* environment variables.
+func execHook(command string) func(context.Context, watchHookEvent) error {
+	return func(ctx context.Context, event watchHookEvent) error {
+		object, err := json.Marshal(event.Object)
+		if err != nil {
+			return err
+		}
+		shell, flag := "sh", "-c"
+		if runtime.GOOS == "windows" {
+			shell, flag = "cmd", "/C"
+		}
+		cmd := exec.CommandContext(ctx, shell, flag, command)
+		cmd.Stdin = bytes.NewReader(object)
+		cmd.Env = append(os.Environ(),
+			"K8CTL_EVENT="+event.Type,
+			"K8CTL_RULE="+event.Rule,
+			"K8CTL_KIND="+event.Kind,
+			"K8CTL_NAMESPACE="+event.Namespace,
+			"K8CTL_NAME="+event.Name,
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			if lines := strings.Split(strings.TrimSpace(string(out)), "\n"); lines[len(lines)-1] != "" {
+				return fmt.Errorf("%v: %s", err, lines[len(lines)-1])
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// webhookHook POSTs the event as JSON to endpoint.
+func webhookHook(endpoint string) func(context.Context, watchHookEvent) error {
+	return func(ctx context.Context, event watchHookEvent) error {
+		body, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook returned %s", resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,354 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestParseWatchRule(t *testing.T) {
+	rule, err := parseWatchRule("status=CrashLoopBackOff, restarts>=3,metadata.labels.app!=web")
+	if err != nil {
+		t.Fatalf("parseWatchRule failed: %v", err)
+	}
+	expected := []watchCondition{
+		{field: "status", op: "=", value: "CrashLoopBackOff"},
+		{field: "restarts", op: ">=", value: "3"},
+		{field: "metadata.labels.app", op: "!=", value: "web"},
+	}
+	if len(rule.conditions) != len(expected) {
+		t.Fatalf("Expected %d conditions, got %v", len(expected), rule.conditions)
+	}
+	for i, condition := range rule.conditions {
+		if condition != expected[i] {
+			t.Errorf("Condition %d = %+v, expected %+v", i, condition, expected[i])
+		}
+	}
+
+	for _, text := range []string{"", "status", "=Running", "status=Running,", "restarts=>3", "status=a=b", "metadata.labels[app=api"} {
+		if _, err := parseWatchRule(text); err == nil {
+			t.Errorf("Expected parseWatchRule(%q) to fail", text)
+		}
+	}
+}
+
+func TestWatchRuleMatches(t *testing.T) {
+	res := &resolvedResource{gvr: podResource, kind: "Pod", namespaced: true}
+	table := newWatchTable(nil, res)
+	pod := unstructuredPod(t, "api-0", corev1.PodRunning)
+	if err := unstructured.SetNestedSlice(pod.Object, []interface{}{
+		map[string]interface{}{"name": "app", "ready": false, "restartCount": int64(4)},
+	}, "status", "containerStatuses"); err != nil {
+		t.Fatalf("failed to set container statuses: %v", err)
+	}
+	pod.SetLabels(map[string]string{"app": "api", "app.kubernetes.io/name": "api"})
+
+	tests := []struct {
+		rule     string
+		expected bool
+	}{
+		{"status=running", true},
+		{"STATUS=Pending", false},
+		{"status.phase=Running", true},
+		{"metadata.labels.app=api", true},
+		{"metadata.labels.tier=api", false},
+		{"metadata.labels[app.kubernetes.io/name]=api", true},
+		{"metadata.labels['app.kubernetes.io/name']=api", true},
+		{`metadata.labels.app\.kubernetes\.io/name=api`, true},
+		{"metadata.labels.app.kubernetes.io/name=api", false},
+		{"restarts>3", true},
+		{"restarts>4", false},
+		{"type=MODIFIED,status!=Pending", true},
+		{"type=DELETED", false},
+	}
+	for _, tt := range tests {
+		rule, err := parseWatchRule(tt.rule)
+		if err != nil {
+			t.Fatalf("parseWatchRule(%q) failed: %v", tt.rule, err)
+		}
+		if matched := rule.matches("MODIFIED", pod, table); matched != tt.expected {
+			t.Errorf("Rule %q matched = %v, expected %v", tt.rule, matched, tt.expected)
+		}
+	}
+}
+
+func TestSplitFieldPath(t *testing.T) {
+	tests := map[string][]string{
+		"status.phase": {"status", "phase"},
+		"metadata.labels[app.kubernetes.io/name]":   {"metadata", "labels", "app.kubernetes.io/name"},
+		`metadata.labels["app.kubernetes.io/name"]`: {"metadata", "labels", "app.kubernetes.io/name"},
+		`metadata.labels.app\.kubernetes\.io/name`:  {"metadata", "labels", "app.kubernetes.io/name"},
+		"metadata[annotations][a.b].x":              {"metadata", "annotations", "a.b", "x"},
+	}
+	for path, expected := range tests {
+		keys, err := splitFieldPath(path)
+		if err != nil || strings.Join(keys, "|") != strings.Join(expected, "|") {
+			t.Errorf("splitFieldPath(%q) = %q, %v, expected %q", path, keys, err, expected)
+		}
+	}
+
+	for _, path := range []string{"", "status.", ".phase", "a..b", "labels[a", "labels[]", "labels[a]b", `labels\`} {
+		if _, err := splitFieldPath(path); err == nil {
+			t.Errorf("Expected splitFieldPath(%q) to fail", path)
+		}
+	}
+}
+
+func TestWatchHooksFireOnTransitions(t *testing.T) {
+	res := &resolvedResource{gvr: podResource, kind: "Pod", namespaced: true}
+	rule, _ := parseWatchRule("status=Failed")
+	deleted, _ := parseWatchRule("type=DELETED")
+	opts := watchOptions{hookRules: []watchRule{rule, deleted}, hookCooldown: time.Minute, hookRate: 3}
+
+	var mu sync.Mutex
+	var reports, fired []string
+	hooks := newWatchHooks(newWatchTable(nil, res), opts, func(line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, line)
+	})
+	hooks.actions = []watchHookAction{{name: "test", run: func(_ context.Context, event watchHookEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		fired = append(fired, event.Type+" "+event.Name+" "+event.Rule)
+		return nil
+	}}}
+	now := time.Date(2024, 1, 2, 14, 0, 0, 0, time.UTC)
+	hooks.now = func() time.Time { return now }
+	handler := hooks.handler(context.Background())
+
+	running := unstructuredPod(t, "api-0", corev1.PodRunning)
+	failed := unstructuredPod(t, "api-0", corev1.PodFailed)
+	// Objects already failing when the watch starts do not fire
+	handler.OnAdd(unstructuredPod(t, "api-9", corev1.PodFailed), true)
+	handler.OnUpdate(running, failed)
+	// Still failing, so not a transition
+	handler.OnUpdate(failed, failed.DeepCopy())
+	// Flapping within the cooldown
+	handler.OnUpdate(failed, running)
+	handler.OnUpdate(running, failed)
+	now = now.Add(2 * time.Minute)
+	handler.OnUpdate(failed, running)
+	handler.OnUpdate(running, failed)
+	// Field rules that already held do not fire on deletion
+	handler.OnDelete(failed)
+	hooks.wait()
+
+	sort.Strings(fired)
+	expected := []string{"DELETED api-0 type=DELETED", "MODIFIED api-0 status=Failed", "MODIFIED api-0 status=Failed"}
+	if strings.Join(fired, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected hooks:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(fired, "\n"))
+	}
+	if !containsString(reports, "Hook test for pod/api-0 (status=Failed) ran at 14:02:00") {
+		t.Errorf("Expected the hook outcome to be reported, got %v", reports)
+	}
+}
+
+func TestWatchHooksRedactSecrets(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	res := &resolvedResource{gvr: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, kind: "Secret", namespaced: true}
+	rule, _ := parseWatchRule("type=MODIFIED")
+	// A secret created by kubectl apply, which repeats its data in an annotation
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{"name": "db", "namespace": "default", "annotations": map[string]interface{}{
+			corev1.LastAppliedConfigAnnotation: `{"data":{"password":"aHVudGVyMg=="}}`,
+		}},
+		"data": map[string]interface{}{"password": "aHVudGVyMg=="},
+	}}
+
+	for _, showSecrets := range []bool{false, true} {
+		bodies = nil
+		opts := watchOptions{hookRules: []watchRule{rule}, hookWebhook: server.URL, hookRate: 1, showSecrets: showSecrets}
+		hooks := newWatchHooks(newWatchTable(nil, res), opts, func(string) {})
+		hooks.handler(context.Background()).OnUpdate(nil, secret)
+		hooks.wait()
+
+		if len(bodies) != 1 {
+			t.Fatalf("Expected one webhook call, got %d", len(bodies))
+		}
+		var event watchHookEvent
+		if err := json.Unmarshal([]byte(bodies[0]), &event); err != nil {
+			t.Fatalf("Expected a JSON payload, got %q: %v", bodies[0], err)
+		}
+		password, _, _ := unstructured.NestedString(event.Object, "data", "password")
+		redacted := password == "<redacted: 7 bytes>" &&
+			!strings.Contains(bodies[0], "aHVudGVyMg==") &&
+			!strings.Contains(bodies[0], corev1.LastAppliedConfigAnnotation)
+		if redacted == showSecrets {
+			t.Errorf("showSecrets=%v: unexpected payload %s", showSecrets, bodies[0])
+		}
+	}
+	if password, _, _ := unstructured.NestedString(secret.Object, "data", "password"); password != "aHVudGVyMg==" {
+		t.Errorf("Expected the cached object to be left alone, got %q", password)
+	}
+}
+
+func TestWatchHooksRateLimit(t *testing.T) {
+	res := &resolvedResource{gvr: podResource, kind: "Pod", namespaced: true}
+	rule, _ := parseWatchRule("type=ADDED")
+	var reports []string
+	hooks := newWatchHooks(newWatchTable(nil, res), watchOptions{hookRules: []watchRule{rule}, hookRate: 2}, func(line string) {
+		reports = append(reports, line)
+	})
+	hooks.actions = nil
+	handler := hooks.handler(context.Background())
+	for _, name := range []string{"api-0", "api-1", "api-2"} {
+		handler.OnAdd(unstructuredPod(t, name, corev1.PodPending), false)
+	}
+	hooks.wait()
+
+	expected := "Hook for pod/api-2 (type=ADDED) skipped: more than 2 hooks per minute"
+	if len(reports) != 1 || reports[0] != expected {
+		t.Errorf("Expected %q, got %v", expected, reports)
+	}
+}
+
+func TestExecHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	dir := t.TempDir()
+	event := watchHookEvent{
+		Type:      "MODIFIED",
+		Rule:      "status=Failed",
+		Kind:      "Pod",
+		Namespace: "default",
+		Name:      "api-0",
+		Object:    map[string]interface{}{"kind": "Pod"},
+	}
+
+	command := `cat > object.json && echo "$K8CTL_EVENT $K8CTL_RULE $K8CTL_KIND $K8CTL_NAMESPACE/$K8CTL_NAME" > env.txt`
+	if err := execHook("cd "+dir+" && "+command)(context.Background(), event); err != nil {
+		t.Fatalf("execHook failed: %v", err)
+	}
+	object, _ := os.ReadFile(filepath.Join(dir, "object.json"))
+	if strings.TrimSpace(string(object)) != `{"kind":"Pod"}` {
+		t.Errorf("Expected the object on stdin, got %q", object)
+	}
+	env, _ := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if strings.TrimSpace(string(env)) != "MODIFIED status=Failed Pod default/api-0" {
+		t.Errorf("Unexpected environment %q", env)
+	}
+
+	err := execHook("echo not allowed >&2; exit 3")(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("Expected the command's error output, got %v", err)
+	}
+}
+
+func TestWebhookHook(t *testing.T) {
+	var received watchHookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil || received.Name == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	event := watchHookEvent{Type: "DELETED", Rule: "type=DELETED", Kind: "Pod", Name: "api-0"}
+	if err := webhookHook(server.URL)(context.Background(), event); err != nil {
+		t.Fatalf("webhookHook failed: %v", err)
+	}
+	if received.Name != "api-0" || received.Rule != "type=DELETED" {
+		t.Errorf("Unexpected payload %+v", received)
+	}
+
+	event.Name = "fail"
+	if err := webhookHook(server.URL)(context.Background(), event); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Expected the status to be reported, got %v", err)
+	}
+}
+
+func TestCompleteHooks(t *testing.T) {
+	valid := watchOptions{hookOn: []string{"status=Failed", "type=DELETED"}, hookExec: "true", hookRate: 1}
+	if err := valid.completeHooks(); err != nil || len(valid.hookRules) != 2 {
+		t.Errorf("Expected two rules, got %v, %v", valid.hookRules, err)
+	}
+
+	for name, opts := range map[string]watchOptions{
+		"no action":   {hookOn: []string{"status=Failed"}, hookRate: 1},
+		"no rule":     {hookWebhook: "http://localhost:9000/hook", hookRate: 1},
+		"bad url":     {hookOn: []string{"status=Failed"}, hookWebhook: "localhost:9000", hookRate: 1},
+		"zero rate":   {hookOn: []string{"status=Failed"}, hookExec: "true"},
+		"bad rule":    {hookOn: []string{"status"}, hookExec: "true", hookRate: 1},
+		"negative cd": {hookOn: []string{"status=Failed"}, hookExec: "true", hookRate: 1, hookCooldown: -time.Second},
+	} {
+		if err := opts.completeHooks(); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestWatchResourceRunsHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{podResource: "PodList"},
+		unstructuredPod(t, "api-0", corev1.PodRunning),
+	)
+	res := &resolvedResource{gvr: podResource, kind: "Pod", namespaced: true}
+
+	var out syncBuffer
+	opts := watchOptions{debounce: 10 * time.Millisecond, out: &out, hookOn: []string{"type=ADDED"}, hookExec: "true", hookRate: 1}
+	if err := opts.completeHooks(); err != nil {
+		t.Fatalf("completeHooks failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watchResource(ctx, client, res, "default", newWatchTable(client, res), opts)
+	}()
+
+	waitForOutput(t, &out, "api-0")
+	if _, err := client.Resource(podResource).Namespace("default").Create(
+		context.Background(), unstructuredPod(t, "api-1", corev1.PodPending), metav1.CreateOptions{},
+	); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	waitForOutput(t, &out, "Hook exec for pod/api-1 (type=ADDED) ran at")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("watchResource returned %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// watchRecordFormat is the version of the recording format
+	watchRecordFormat = 1
+	// watchRecordSession is the type of the record that opens a recording
+	watchRecordSession = "SESSION"
+	// watchRecordMaxLine bounds the size of one recorded object
+	watchRecordMaxLine = 16 * 1024 * 1024
+)
+
+// watchRecord is one line of a recording: the session header, then every
+// event with the full object it carried.
+type watchRecord struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	// Initial marks objects of the initial list rather than changes
+	Initial bool                   `json:"initial,omitempty"`
+	Session *watchSession          `json:"session,omitempty"`
+	Object  map[string]interface{} `json:"object,omitempty"`
+}
+
+// watchSession describes what a recording watched, so a replay can render
+// the same table without a cluster.
+type watchSession struct {
+	Format        int                  `json:"format"`
+	Group         string               `json:"group,omitempty"`
+	Version       string               `json:"version"`
+	Resource      string               `json:"resource"`
+	Kind          string               `json:"kind"`
+	Namespaced    bool                 `json:"namespaced"`
+	Namespace     string               `json:"namespace,omitempty"`
+	LabelSelector string               `json:"labelSelector,omitempty"`
+	FieldSelector string               `json:"fieldSelector,omitempty"`
+	Columns       []watchSessionColumn `json:"columns,omitempty"`
+}
+
+// watchSessionColumn is a printer column of a recorded custom resource.
+type watchSessionColumn struct {
+	Name     string `json:"name"`
+	JSONPath string `json:"jsonPath"`
+	Type     string `json:"type,omitempty"`
+}
+
+func newWatchSession(res *resolvedResource, namespace string, table watchTable, opts watchOptions) watchSession {
+	session := watchSession{
+		Format:        watchRecordFormat,
+		Group:         res.gvr.Group,
+		Version:       res.gvr.Version,
+		Resource:      res.gvr.Resource,
+		Kind:          res.kind,
+		Namespaced:    res.namespaced,
+		Namespace:     namespace,
+		LabelSelector: opts.listOpts.LabelSelector,
+		FieldSelector: opts.listOpts.FieldSelector,
+	}
+	for _, col := range table.columns {
+		session.Columns = append(session.Columns, watchSessionColumn{Name: col.name, JSONPath: col.jsonPath, Type: col.colType})
+	}
+	return session
+}
+
+func (s watchSession) resource() *resolvedResource {
+	return &resolvedResource{
+		gvr:        schema.GroupVersionResource{Group: s.Group, Version: s.Version, Resource: s.Resource},
+		kind:       s.Kind,
+		namespaced: s.Namespaced,
+	}
+}
+
+// table rebuilds the table the session was watched with.
+func (s watchSession) table() watchTable {
+	res := s.resource()
+	if table, ok := builtinWatchTable(res); ok {
+		return table
+	}
+	columns := make([]printerColumn, 0, len(s.Columns))
+	for _, col := range s.Columns {
+		columns = append(columns, printerColumn{name: col.Name, jsonPath: col.JSONPath, colType: col.Type})
+	}
+	if len(columns) == 0 {
+		columns = defaultPrinterColumns()
+	}
+	return printerColumnsWatchTable(res, compileColumns(columns))
+}
+
+// createRecording creates or truncates a recording file. Recordings hold
+// full objects, so only the owner may read them.
+func createRecording(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+}
+
+// watchRecorder appends every event of a watch to a recording.
+type watchRecorder struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	now     func() time.Time
+	// showSecrets records secret values instead of redacting them
+	showSecrets bool
+	// writeErr is the first failed write; later events are dropped
+	writeErr error
+}
+
+func newWatchRecorder(out io.Writer, showSecrets bool) *watchRecorder {
+	r := &watchRecorder{encoder: json.NewEncoder(out), now: time.Now, showSecrets: showSecrets}
+	r.encoder.SetEscapeHTML(false)
+	return r
+}
+
+// begin writes the header that opens the recording of session.
+func (r *watchRecorder) begin(session watchSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.encoder.Encode(watchRecord{Time: r.now(), Type: watchRecordSession, Session: &session})
+}
+
+// handler records the initial list and every change after it. Updates that
+// repeat a known resourceVersion, as a relist does, are left out.
+func (r *watchRecorder) handler() cache.ResourceEventHandlerDetailedFuncs {
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) { r.record("ADDED", obj, isInInitialList) },
+		UpdateFunc: func(old, obj interface{}) {
+			previous, ok := old.(*unstructured.Unstructured)
+			current, isCurrent := obj.(*unstructured.Unstructured)
+			if ok && isCurrent && previous.GetResourceVersion() != "" &&
+				previous.GetResourceVersion() == current.GetResourceVersion() {
+				return
+			}
+			r.record("MODIFIED", obj, false)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			r.record("DELETED", obj, false)
+		},
+	}
+}
+
+func (r *watchRecorder) record(eventType string, obj interface{}, initial bool) {
+	item, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	object := item.Object
+	if !r.showSecrets && isSecretObject(object) {
+		object = item.DeepCopy().Object
+		_ = redactSecretObject(object, false)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.writeErr != nil {
+		return
+	}
+	r.writeErr = r.encoder.Encode(watchRecord{Time: r.now(), Type: eventType, Initial: initial, Object: object})
+}
+
+// err returns the first error writing the recording.
+func (r *watchRecorder) err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.writeErr
+}
+
+// watchRecording is a recording read back for replay.
+type watchRecording struct {
+	session watchSession
+	start   time.Time
+	events  []watchRecord
+}
+
+// readWatchRecording parses a recording written by watch --record.
+func readWatchRecording(in io.Reader) (*watchRecording, error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), watchRecordMaxLine)
+
+	var recording *watchRecording
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record watchRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if recording == nil {
+			if record.Type != watchRecordSession || record.Session == nil {
+				return nil, fmt.Errorf("not a watch recording: line %d is not a session header", line)
+			}
+			if record.Session.Format > watchRecordFormat {
+				return nil, fmt.Errorf("recording format %d is newer than supported (%d)", record.Session.Format, watchRecordFormat)
+			}
+			recording = &watchRecording{session: *record.Session, start: record.Time}
+			continue
+		}
+		if record.Object == nil {
+			return nil, fmt.Errorf("line %d: %s event without an object", line, record.Type)
+		}
+		recording.events = append(recording.events, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if recording == nil {
+		return nil, fmt.Errorf("not a watch recording: no session header")
+	}
+	return recording, nil
+}
+
+// end returns the time of the last recorded event.
+func (r *watchRecording) end() time.Time {
+	if len(r.events) == 0 {
+		return r.start
+	}
+	return r.events[len(r.events)-1].Time
+}
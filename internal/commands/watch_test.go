@@ -0,0 +1,248 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertusnegoro/k8ctl/internal/output"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var podResource = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+func unstructuredPod(t *testing.T, name string, phase corev1.PodPhase) *unstructured.Unstructured {
+	t.Helper()
+	pod := testLogPod(name, map[string]string{"app": "api"}, "app")
+	pod.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}
+	pod.Status.Phase = phase
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		t.Fatalf("failed to convert pod: %v", err)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func waitForOutput(t *testing.T, out *syncBuffer, expected string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), expected) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %q, got:\n%s", expected, out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchResource(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podResource: "PodList"},
+		unstructuredPod(t, "api-0", corev1.PodRunning),
+	)
+	res := &resolvedResource{gvr: podResource, kind: "Pod", namespaced: true}
+
+	var out syncBuffer
+	opts := watchOptions{debounce: 10 * time.Millisecond, out: &out}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watchResource(ctx, client, res, "default", newWatchTable(client, res), opts)
+	}()
+
+	waitForOutput(t, &out, "api-0")
+	if strings.Contains(out.String(), "Event:") {
+		t.Errorf("Objects of the initial list should not be reported as events:\n%s", out.String())
+	}
+
+	if _, err := client.Resource(podResource).Namespace("default").Create(
+		context.Background(), unstructuredPod(t, "api-1", corev1.PodPending), metav1.CreateOptions{},
+	); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	waitForOutput(t, &out, "Event: ADDED - default/api-1")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watchResource failed: %v", err)
+	}
+	// The redraw after the event only rewrote the changed lines, so the
+	// header is drawn once
+	if count := strings.Count(out.String(), "RESTARTS"); count != 1 {
+		t.Errorf("Expected the header to be drawn once, got %d times:\n%s", count, out.String())
+	}
+}
+
+func TestWatchResourceReportsReconnects(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podResource: "PodList"},
+		unstructuredPod(t, "api-0", corev1.PodRunning),
+	)
+	closing, expiring := watch.NewFake(), watch.NewFake()
+	calls := 0
+	client.PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		calls++
+		switch calls {
+		case 1:
+			return true, closing, nil
+		case 2:
+			return true, expiring, nil
+		}
+		return false, nil, nil
+	})
+	res := &resolvedResource{gvr: podResource, kind: "Pod", namespaced: true}
+
+	var out syncBuffer
+	opts := watchOptions{debounce: 10 * time.Millisecond, out: &out}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watchResource(ctx, client, res, "default", newWatchTable(client, res), opts)
+	}()
+	waitForOutput(t, &out, "api-0")
+
+	pod := unstructuredPod(t, "api-1", corev1.PodPending)
+	pod.SetResourceVersion("7")
+	closing.Add(pod)
+	waitForOutput(t, &out, "Event: ADDED - default/api-1")
+	closing.Stop()
+	waitForOutput(t, &out, "after watch closed at resourceVersion 7, 1 reconnect so far")
+
+	expiring.Error(&metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusGone,
+		Reason: metav1.StatusReasonExpired,
+	})
+	waitForOutput(t, &out, "after 410 Gone at resourceVersion 7, 2 reconnects so far")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watchResource failed: %v", err)
+	}
+}
+
+func TestWatchTableRender(t *testing.T) {
+	if output.IsColorEnabled() {
+		output.DisableColors()
+		defer output.EnableColors()
+	}
+
+	client := newCertificateClient()
+	res := &resolvedResource{gvr: certificateResource, kind: "Certificate", namespaced: true}
+	table := newWatchTable(client, res)
+	if strings.Join(table.headers, ",") != "NAME,READY,SECRET" {
+		t.Errorf("Expected the printer columns of the CRD, got %v", table.headers)
+	}
+
+	cert, err := client.Resource(certificateResource).Namespace("default").Get(context.Background(), "api-tls", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get certificate: %v", err)
+	}
+	lines := table.render([]interface{}{cert}, columnOptions{showKind: true}, "Event: MODIFIED - default/api-tls", "")
+	result := strings.Join(lines, "\n")
+	for _, expected := range []string{"certificate.cert-manager.io/api-tls", "True", "Watching for changes", "Event: MODIFIED - default/api-tls"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, result)
+		}
+	}
+}
+
+func TestBuiltinWatchTables(t *testing.T) {
+	replicas := int32(3)
+	tests := []struct {
+		name    string
+		gvr     schema.GroupVersionResource
+		kind    string
+		obj     interface{}
+		headers []string
+		row     []string
+	}{
+		{
+			name: "deployments share the get columns",
+			gvr:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			kind: "Deployment",
+			// A rollout surging one pod above the desired count
+			obj: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "api"},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{Replicas: 4, ReadyReplicas: 2},
+			},
+			headers: deploymentHeaders(false),
+			row:     []string{"api", "2/3"},
+		},
+		{
+			name:    "nodes share the get columns",
+			gvr:     schema.GroupVersionResource{Version: "v1", Resource: "nodes"},
+			kind:    "Node",
+			obj:     &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+			headers: nodeHeaders(false),
+			row:     []string{"node-1"},
+		},
+		{
+			name: "statefulsets get kubectl's columns",
+			gvr:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
+			kind: "StatefulSet",
+			obj: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "db"},
+				Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+			},
+			headers: []string{"NAME", "READY", "AGE"},
+			row:     []string{"db", "1/1"},
+		},
+		{
+			name: "jobs get kubectl's columns",
+			gvr:  schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"},
+			kind: "Job",
+			obj: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "migrate"},
+				Spec:       batchv1.JobSpec{Completions: &replicas},
+				Status:     batchv1.JobStatus{Succeeded: 2},
+			},
+			headers: []string{"NAME", "COMPLETIONS", "DURATION", "AGE"},
+			row:     []string{"migrate", "2/3", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, ok := builtinWatchTable(&resolvedResource{gvr: tt.gvr, kind: tt.kind, namespaced: true})
+			if !ok {
+				t.Fatalf("Expected a built-in table for %s", tt.gvr.Resource)
+			}
+			if strings.Join(table.headers, ",") != strings.Join(tt.headers, ",") {
+				t.Errorf("Expected headers %v, got %v", tt.headers, table.headers)
+			}
+			row := table.row(&unstructured.Unstructured{Object: toUnstructuredMap(t, tt.obj)})
+			if len(row) != len(table.headers) {
+				t.Fatalf("Expected %d columns, got %v", len(table.headers), row)
+			}
+			for i, value := range tt.row {
+				if row[i] != value {
+					t.Errorf("Expected %s %q, got %q", table.headers[i], value, row[i])
+				}
+			}
+		})
+	}
+}
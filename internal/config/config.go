@@ -21,6 +21,7 @@ type Config struct {
 	Aliases        map[string]string `mapstructure:"aliases"`
 	Colors         ColorConfig       `mapstructure:"colors"`
 	Output         OutputConfig      `mapstructure:"output"`
+	Logs           LogsConfig        `mapstructure:"logs"`
 }
 
 // ColorConfig represents color output configuration.
@@ -33,6 +34,43 @@ type OutputConfig struct {
 	Format string `mapstructure:"format"` // table, json, yaml
 }
 
+// LogsConfig names the fields read from structured (JSON or logfmt) log
+// lines. The first field present in a line wins.
+type LogsConfig struct {
+	LevelFields   []string `mapstructure:"level_fields"`
+	TimeFields    []string `mapstructure:"time_fields"`
+	MessageFields []string `mapstructure:"message_fields"`
+	CallerFields  []string `mapstructure:"caller_fields"`
+}
+
+// DefaultLogsConfig returns the field names used by common logging libraries.
+func DefaultLogsConfig() LogsConfig {
+	return LogsConfig{
+		LevelFields:   []string{"level", "lvl", "severity", "log.level"},
+		TimeFields:    []string{"time", "ts", "timestamp", "@timestamp"},
+		MessageFields: []string{"msg", "message", "@message"},
+		CallerFields:  []string{"caller", "logger", "source"},
+	}
+}
+
+// WithDefaults fills the field lists left empty with the defaults.
+func (c LogsConfig) WithDefaults() LogsConfig {
+	defaults := DefaultLogsConfig()
+	if len(c.LevelFields) == 0 {
+		c.LevelFields = defaults.LevelFields
+	}
+	if len(c.TimeFields) == 0 {
+		c.TimeFields = defaults.TimeFields
+	}
+	if len(c.MessageFields) == 0 {
+		c.MessageFields = defaults.MessageFields
+	}
+	if len(c.CallerFields) == 0 {
+		c.CallerFields = defaults.CallerFields
+	}
+	return c
+}
+
 var (
 	configDir  string
 	configPath string
@@ -78,6 +116,11 @@ func Load() (*Config, error) {
 	})
 	viper.SetDefault("colors.enabled", true)
 	viper.SetDefault("output.format", "table")
+	logs := DefaultLogsConfig()
+	viper.SetDefault("logs.level_fields", logs.LevelFields)
+	viper.SetDefault("logs.time_fields", logs.TimeFields)
+	viper.SetDefault("logs.message_fields", logs.MessageFields)
+	viper.SetDefault("logs.caller_fields", logs.CallerFields)
 
 	// Create config directory if it doesn't exist
 	if err := os.MkdirAll(configDir, 0o755); err != nil {
@@ -101,6 +144,7 @@ func Load() (*Config, error) {
 				},
 				Colors: ColorConfig{Enabled: true},
 				Output: OutputConfig{Format: "table"},
+				Logs:   DefaultLogsConfig(),
 			}); err != nil {
 				return nil, fmt.Errorf("failed to create default config: %w", err)
 			}
@@ -124,6 +168,10 @@ func Save(c *Config) error {
 	viper.Set("aliases", c.Aliases)
 	viper.Set("colors.enabled", c.Colors.Enabled)
 	viper.Set("output.format", c.Output.Format)
+	viper.Set("logs.level_fields", c.Logs.LevelFields)
+	viper.Set("logs.time_fields", c.Logs.TimeFields)
+	viper.Set("logs.message_fields", c.Logs.MessageFields)
+	viper.Set("logs.caller_fields", c.Logs.CallerFields)
 
 	if err := viper.WriteConfigAs(configPath); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
@@ -144,6 +192,7 @@ func Get() *Config {
 				Aliases: make(map[string]string),
 				Colors:  ColorConfig{Enabled: true},
 				Output:  OutputConfig{Format: "table"},
+				Logs:    DefaultLogsConfig(),
 			}
 		}
 	}
@@ -54,6 +54,9 @@ func TestLoadAndSave(t *testing.T) {
 	if cfg.Output.Format != "table" {
 		t.Error("Default output.format should be 'table'")
 	}
+	if len(cfg.Logs.MessageFields) == 0 || cfg.Logs.MessageFields[0] != "msg" {
+		t.Errorf("Default logs.message_fields should start with 'msg', got %v", cfg.Logs.MessageFields)
+	}
 
 	// Test saving config
 	cfg.CurrentContext = "test-context"
@@ -138,3 +141,13 @@ func TestGetWithNilConfig(t *testing.T) {
 		t.Error("Get() should return a config even if cfg is nil")
 	}
 }
+
+func TestLogsConfigWithDefaults(t *testing.T) {
+	logs := LogsConfig{LevelFields: []string{"severity_text"}}.WithDefaults()
+	if len(logs.LevelFields) != 1 || logs.LevelFields[0] != "severity_text" {
+		t.Errorf("Configured level fields should be kept, got %v", logs.LevelFields)
+	}
+	if len(logs.MessageFields) == 0 || len(logs.TimeFields) == 0 || len(logs.CallerFields) == 0 {
+		t.Errorf("Empty field lists should fall back to defaults, got %+v", logs)
+	}
+}
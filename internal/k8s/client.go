@@ -6,15 +6,22 @@ import (
 	"path/filepath"
 
 	"github.com/robertusnegoro/k8ctl/internal/config"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 )
 
 var (
-	clientset  *kubernetes.Clientset
-	restConfig *rest.Config
+	clientset     *kubernetes.Clientset
+	restConfig    *rest.Config
+	dynamicClient dynamic.Interface
+	restMapper    meta.RESTMapper
 )
 
 // GetClient returns the Kubernetes clientset
@@ -36,6 +43,47 @@ func GetClient() (*kubernetes.Clientset, error) {
 	return clientset, nil
 }
 
+// GetDynamicClient returns a dynamic client for working with arbitrary resource types
+func GetDynamicClient() (dynamic.Interface, error) {
+	if dynamicClient != nil {
+		return dynamicClient, nil
+	}
+
+	cfg, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err = dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamicClient, nil
+}
+
+// GetRESTMapper returns a discovery-backed REST mapper that also understands short names
+func GetRESTMapper() (meta.RESTMapper, error) {
+	if restMapper != nil {
+		return restMapper, nil
+	}
+
+	cfg, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Discovery is fetched lazily and cached for the lifetime of the process
+	cached := memory.NewMemCacheClient(discoveryClient)
+	restMapper = restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(cached), cached, nil)
+	return restMapper, nil
+}
+
 // GetConfig returns the Kubernetes REST config
 func GetConfig() (*rest.Config, error) {
 	if restConfig != nil {
@@ -87,4 +135,6 @@ func GetConfig() (*rest.Config, error) {
 func ResetClient() {
 	clientset = nil
 	restConfig = nil
+	dynamicClient = nil
+	restMapper = nil
 }
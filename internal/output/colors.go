@@ -2,6 +2,9 @@
 package output
 
 import (
+	"hash/fnv"
+	"strings"
+
 	"github.com/fatih/color"
 )
 
@@ -39,22 +42,66 @@ var (
 	Warning = color.New(color.FgYellow)
 	// Error is the color for error messages.
 	Error = color.New(color.FgRed)
+	// Dim is the color for secondary details such as extra log fields.
+	Dim = color.New(color.Faint)
+	// MatchHighlight is the color for text matched by a search.
+	MatchHighlight = color.New(color.FgBlack, color.BgYellow)
 )
 
+// prefixColors are cycled through to tell log sources apart
+var prefixColors = []*color.Color{
+	color.New(color.FgCyan),
+	color.New(color.FgGreen),
+	color.New(color.FgMagenta),
+	color.New(color.FgYellow),
+	color.New(color.FgBlue),
+	color.New(color.FgHiCyan),
+	color.New(color.FgHiGreen),
+	color.New(color.FgHiMagenta),
+	color.New(color.FgHiYellow),
+	color.New(color.FgHiBlue),
+}
+
+// PrefixColor returns a color derived from key, so each log source keeps the
+// same color for as long as it is streamed
+func PrefixColor(key string) *color.Color {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return prefixColors[h.Sum32()%uint32(len(prefixColors))]
+}
+
 // ColorizeStatus returns a colored string based on status
 func ColorizeStatus(status string) string {
 	switch status {
 	case "Running", "Ready", "Active", "Succeeded", "Completed":
 		return StatusRunning.Sprint(status)
-	case "Pending", "ContainerCreating", "PodInitializing":
+	case "Pending", "ContainerCreating", "PodInitializing", "Terminating":
 		return StatusPending.Sprint(status)
-	case "Failed", "Error", "CrashLoopBackOff", "ImagePullBackOff":
-		return StatusFailed.Sprint(status)
 	case "Warning", "Unknown":
 		return StatusWarning.Sprint(status)
-	default:
-		return status
 	}
+
+	if IsFailedStatus(status) {
+		return StatusFailed.Sprint(status)
+	}
+	// Init container progress, e.g. "Init:1/3"
+	if strings.HasPrefix(status, "Init:") {
+		return StatusPending.Sprint(status)
+	}
+	return status
+}
+
+// IsFailedStatus reports whether a pod or container status describes a failure,
+// including init container failures such as "Init:CrashLoopBackOff"
+func IsFailedStatus(status string) bool {
+	status = strings.TrimPrefix(status, "Init:")
+	switch status {
+	case "Failed", "Error", "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull",
+		"OOMKilled", "Evicted", "NodeLost", "ContainerCannotRun", "CreateContainerError",
+		"CreateContainerConfigError", "InvalidImageName", "DeadlineExceeded":
+		return true
+	}
+	return strings.HasPrefix(status, "ExitCode:") || strings.HasPrefix(status, "Signal:")
 }
 
 // IsColorEnabled checks if color output is enabled
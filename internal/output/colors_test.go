@@ -45,3 +45,43 @@ func TestDisableAndEnableColors(_ *testing.T) {
 	enabled = IsColorEnabled()
 	_ = enabled // Just check it doesn't panic
 }
+
+func TestIsFailedStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected bool
+	}{
+		{"CrashLoopBackOff", true},
+		{"OOMKilled", true},
+		{"Evicted", true},
+		{"ExitCode:1", true},
+		{"Signal:9", true},
+		{"Init:CrashLoopBackOff", true},
+		{"Init:ExitCode:2", true},
+		{"Init:1/3", false},
+		{"Running", false},
+		{"Terminating", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if result := IsFailedStatus(tt.status); result != tt.expected {
+				t.Errorf("IsFailedStatus(%q) = %v, expected %v", tt.status, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrefixColor(t *testing.T) {
+	if PrefixColor("api-0") != PrefixColor("api-0") {
+		t.Error("PrefixColor should return the same color for the same key")
+	}
+
+	seen := map[interface{}]bool{}
+	for _, key := range []string{"api-0", "api-1", "api-2", "api-3", "api-4", "api-5"} {
+		seen[PrefixColor(key)] = true
+	}
+	if len(seen) < 2 {
+		t.Error("PrefixColor should spread keys over several colors")
+	}
+}
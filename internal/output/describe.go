@@ -0,0 +1,107 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// describePadding is the gap between aligned describe columns
+const describePadding = 2
+
+// DescribeWriter writes the indented, sectioned text of describe. Lines are
+// buffered until Flush so consecutive fields can be aligned; widths ignore
+// color codes, so any cell may be colorized.
+type DescribeWriter struct {
+	w     io.Writer
+	lines []describeLine
+}
+
+type describeLine struct {
+	indent int
+	cells  []string
+}
+
+// NewDescribeWriter creates a describe writer writing to w
+func NewDescribeWriter(w io.Writer) *DescribeWriter {
+	return &DescribeWriter{w: w}
+}
+
+// Section writes a colored section title, e.g. "Containers:"
+func (d *DescribeWriter) Section(indent int, title string) {
+	d.Row(indent, HeaderColor.Sprint(title+":"))
+}
+
+// Field writes a "Label: value" line. Empty values are shown as <none>.
+func (d *DescribeWriter) Field(indent int, label, value string) {
+	if value == "" {
+		value = "<none>"
+	}
+	d.Row(indent, label+":", value)
+}
+
+// Row writes a line of cells. Consecutive rows with more than one cell are
+// aligned into columns.
+func (d *DescribeWriter) Row(indent int, cells ...string) {
+	d.lines = append(d.lines, describeLine{indent: indent, cells: cells})
+}
+
+// Flush aligns and writes the buffered lines
+func (d *DescribeWriter) Flush() error {
+	for start := 0; start < len(d.lines); {
+		end := start + 1
+		if len(d.lines[start].cells) > 1 {
+			for end < len(d.lines) && len(d.lines[end].cells) > 1 {
+				end++
+			}
+		}
+		if err := d.writeBlock(d.lines[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	d.lines = nil
+	return nil
+}
+
+// writeBlock writes lines whose cells share column widths. The indent counts
+// towards the first column, so nested fields line up with their parents.
+func (d *DescribeWriter) writeBlock(lines []describeLine) error {
+	var widths []int
+	for _, line := range lines {
+		// An empty row is written as a blank line
+		if len(line.cells) == 0 {
+			continue
+		}
+		for i, cell := range line.cells[:len(line.cells)-1] {
+			width := visibleWidth(cell)
+			if i == 0 {
+				width += line.indent * 2
+			}
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], width)
+		}
+	}
+
+	for _, line := range lines {
+		var b strings.Builder
+		b.WriteString(strings.Repeat("  ", line.indent))
+		for i, cell := range line.cells {
+			b.WriteString(cell)
+			if i == len(line.cells)-1 {
+				break
+			}
+			width := visibleWidth(cell)
+			if i == 0 {
+				width += line.indent * 2
+			}
+			b.WriteString(strings.Repeat(" ", widths[i]-width+describePadding))
+		}
+		if _, err := fmt.Fprintln(d.w, strings.TrimRight(b.String(), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDescribeWriterAlignsFields(t *testing.T) {
+	if IsColorEnabled() {
+		DisableColors()
+		defer EnableColors()
+	}
+
+	var buf bytes.Buffer
+	d := NewDescribeWriter(&buf)
+	d.Field(0, "Name", "web")
+	d.Field(0, "Namespace", "default")
+	d.Field(0, "Node", "")
+	d.Section(0, "Containers")
+	d.Section(1, "app")
+	d.Field(2, "Image", "nginx")
+	d.Field(2, "Restart Count", "3")
+	d.Row(1, "Type", "Status")
+	d.Row(1, "Ready", StatusRunning.Sprint("True"))
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	expected := "Name:       web\n" +
+		"Namespace:  default\n" +
+		"Node:       <none>\n" +
+		"Containers:\n" +
+		"  app:\n" +
+		"    Image:          nginx\n" +
+		"    Restart Count:  3\n" +
+		"  Type              Status\n" +
+		"  Ready             True\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestDescribeWriterEmptyRow(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewDescribeWriter(&buf)
+	d.Field(0, "Name", "web")
+	d.Row(1)
+	d.Field(0, "Namespace", "default")
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	expected := "Name:  web\n\nNamespace:  default\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%q\nexpected:\n%q", buf.String(), expected)
+	}
+}
+
+func TestDescribeWriterIgnoresColorWidth(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewDescribeWriter(&buf)
+	d.Row(0, StatusWarning.Sprint("Warning"), "BackOff")
+	d.Row(0, "Normal", "Pulled")
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if visibleWidth(string(lines[0])) != visibleWidth(string(lines[1]))+1 {
+		t.Errorf("Columns are misaligned:\n%s", buf.String())
+	}
+}
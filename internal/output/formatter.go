@@ -3,35 +3,108 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
 
-	yaml "gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
 )
 
 // FormatYAML formats data as YAML
 func FormatYAML(data interface{}) error {
-	yamlData, err := yaml.Marshal(data)
+	return writeYAML(os.Stdout, data)
+}
+
+// FormatJSON formats data as JSON
+func FormatJSON(data interface{}) error {
+	return writeJSON(os.Stdout, data)
+}
+
+func writeYAML(w io.Writer, data interface{}) error {
+	obj := prepareObject(data)
+
+	// sigs.k8s.io/yaml goes through JSON, so the json struct tags are honoured
+	yamlData, err := yaml.Marshal(obj)
 	if err != nil {
 		return fmt.Errorf("failed to marshal YAML: %w", err)
 	}
 
-	_, err = fmt.Fprint(os.Stdout, string(yamlData))
+	_, err = w.Write(yamlData)
 	if err != nil {
 		return fmt.Errorf("failed to write YAML: %w", err)
 	}
 	return nil
 }
 
-// FormatJSON formats data as JSON
-func FormatJSON(data interface{}) error {
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+func writeJSON(w io.Writer, data interface{}) error {
+	obj := prepareObject(data)
+
+	jsonData, err := json.MarshalIndent(obj, "", "    ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	_, err = fmt.Fprint(os.Stdout, string(jsonData))
+	_, err = fmt.Fprintln(w, string(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to write JSON: %w", err)
 	}
 	return nil
 }
+
+// prepareObject turns data into what kubectl would print: typed objects get
+// their apiVersion and kind from the scheme, and slices become a v1 List.
+func prepareObject(data interface{}) interface{} {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		return withTypeMeta(data)
+	}
+
+	items := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		items = append(items, withTypeMeta(v.Index(i).Interface()))
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+		"metadata":   map[string]interface{}{"resourceVersion": ""},
+	}
+}
+
+// withTypeMeta fills in apiVersion and kind, which the typed clients leave empty.
+// Values that are not registered Kubernetes objects are returned unchanged.
+func withTypeMeta(data interface{}) interface{} {
+	obj, ok := data.(runtime.Object)
+	if !ok {
+		// Slices of structs hold values; their pointers implement runtime.Object
+		v := reflect.ValueOf(data)
+		if v.Kind() != reflect.Struct {
+			return data
+		}
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		if obj, ok = ptr.Interface().(runtime.Object); !ok {
+			return data
+		}
+	} else {
+		if v := reflect.ValueOf(obj); v.Kind() == reflect.Ptr && v.IsNil() {
+			return data
+		}
+		// Avoid mutating objects owned by the caller
+		obj = obj.DeepCopyObject()
+	}
+
+	if !obj.GetObjectKind().GroupVersionKind().Empty() {
+		return obj
+	}
+
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil || len(gvks) == 0 {
+		return obj
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	return obj
+}
@@ -1,10 +1,19 @@
 package output
 
 import (
+	"bytes"
+	"flag"
 	"os"
+	"path/filepath"
 	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
 func TestFormatYAML(t *testing.T) {
 	testData := map[string]interface{}{
 		"name":  "test",
@@ -83,3 +92,110 @@ func TestFormatJSONWithComplexData(t *testing.T) {
 		t.Error("FormatJSON should write complex data to stdout")
 	}
 }
+
+func goldenPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "web"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.25"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func goldenDeployments() []appsv1.Deployment {
+	replicas := int32(2)
+	return []appsv1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"},
+		},
+	}
+}
+
+// assertGolden compares output with testdata/<name>, rewriting it when -update is set
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Errorf("Output does not match %s\ngot:\n%s\nexpected:\n%s", path, got, expected)
+	}
+}
+
+func TestFormatGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		golden string
+		data   interface{}
+		write  func(w *bytes.Buffer, data interface{}) error
+	}{
+		{
+			name:   "pod as YAML",
+			golden: "pod.yaml",
+			data:   goldenPod(),
+			write:  func(w *bytes.Buffer, data interface{}) error { return writeYAML(w, data) },
+		},
+		{
+			name:   "pod as JSON",
+			golden: "pod.json",
+			data:   goldenPod(),
+			write:  func(w *bytes.Buffer, data interface{}) error { return writeJSON(w, data) },
+		},
+		{
+			name:   "deployment list as YAML",
+			golden: "deployments.yaml",
+			data:   goldenDeployments(),
+			write:  func(w *bytes.Buffer, data interface{}) error { return writeYAML(w, data) },
+		},
+		{
+			name:   "deployment list as JSON",
+			golden: "deployments.json",
+			data:   goldenDeployments(),
+			write:  func(w *bytes.Buffer, data interface{}) error { return writeJSON(w, data) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(&buf, tt.data); err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			assertGolden(t, tt.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestFormatDoesNotMutateInput(t *testing.T) {
+	pod := goldenPod()
+
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, pod); err != nil {
+		t.Fatalf("writeYAML failed: %v", err)
+	}
+
+	if pod.Kind != "" || pod.APIVersion != "" {
+		t.Errorf("writeYAML should not set TypeMeta on the caller's object, got %s/%s", pod.APIVersion, pod.Kind)
+	}
+}
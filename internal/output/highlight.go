@@ -0,0 +1,74 @@
+package output
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// ansiReset ends every color of an escape sequence
+const ansiReset = "\x1b[0m"
+
+// HighlightMatches colors the parts of s matched by re with c. s may already
+// be colored: matches are found in its visible text, and the surrounding
+// colors resume after each match.
+func HighlightMatches(s string, re *regexp.Regexp, c *color.Color) string {
+	if re == nil || !IsColorEnabled() {
+		return s
+	}
+
+	escapes := ansiPattern.FindAllStringIndex(s, -1)
+	visible := ansiPattern.ReplaceAllString(s, "")
+	matches := re.FindAllStringIndex(visible, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	// inMatch reports whether a visible offset falls inside a match
+	inMatch := func(offset int) bool {
+		for _, match := range matches {
+			if offset >= match[0] && offset < match[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var b strings.Builder
+	var active string
+	offset := 0
+	// writeText copies visible text, highlighting the matched runs
+	writeText := func(text string) {
+		for len(text) > 0 {
+			matched := inMatch(offset)
+			end := 1
+			for end < len(text) && inMatch(offset+end) == matched {
+				end++
+			}
+			if matched {
+				b.WriteString(c.Sprint(text[:end]))
+				b.WriteString(active)
+			} else {
+				b.WriteString(text[:end])
+			}
+			offset += end
+			text = text[end:]
+		}
+	}
+
+	last := 0
+	for _, escape := range escapes {
+		writeText(s[last:escape[0]])
+		sequence := s[escape[0]:escape[1]]
+		b.WriteString(sequence)
+		if sequence == ansiReset {
+			active = ""
+		} else {
+			active += sequence
+		}
+		last = escape[1]
+	}
+	writeText(s[last:])
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestHighlightMatches(t *testing.T) {
+	wasEnabled := IsColorEnabled()
+	EnableColors()
+	defer func() {
+		if !wasEnabled {
+			DisableColors()
+		}
+	}()
+
+	mark := color.New(color.FgBlack, color.BgYellow)
+	red := color.New(color.FgRed)
+	re := regexp.MustCompile("time(out)?")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "plain text",
+			input:    "request timeout after 5s",
+			expected: "request " + mark.Sprint("timeout") + " after 5s",
+		},
+		{
+			name:     "colored text resumes its color",
+			input:    red.Sprint("request timeout after 5s"),
+			expected: "\x1b[31mrequest " + mark.Sprint("timeout") + "\x1b[31m after 5s\x1b[0m",
+		},
+		{
+			name:     "match across color codes",
+			input:    "run" + red.Sprint("time") + "out",
+			expected: "run\x1b[31m" + mark.Sprint("time") + "\x1b[31m\x1b[0m" + mark.Sprint("out"),
+		},
+		{
+			name:     "no match",
+			input:    red.Sprint("all good"),
+			expected: red.Sprint("all good"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HighlightMatches(tt.input, re, mark); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	DisableColors()
+	if got := HighlightMatches("request timeout", re, mark); got != "request timeout" {
+		t.Errorf("Highlighting should be skipped without colors, got %q", got)
+	}
+}
@@ -0,0 +1,378 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Structured output format names accepted by FormatObjects. Formats that take an
+// argument are written as name=argument, e.g. jsonpath={.metadata.name}.
+const (
+	FormatNameJSON              = "json"
+	FormatNameYAML              = "yaml"
+	FormatNameJSONPath          = "jsonpath"
+	FormatNameJSONPathFile      = "jsonpath-file"
+	FormatNameGoTemplate        = "go-template"
+	FormatNameGoTemplateFile    = "go-template-file"
+	FormatNameCustomColumns     = "custom-columns"
+	FormatNameCustomColumnsFile = "custom-columns-file"
+)
+
+// relaxedJSONPathRegex matches the path forms accepted by kubectl: name1.name2,
+// .name1.name2, {name1.name2} and {.name1.name2}
+var relaxedJSONPathRegex = regexp.MustCompile(`^\{\.?([^{}]+)\}$|^\.?([^{}]+)$`)
+
+// IsStructuredFormat reports whether format prints objects instead of a table
+func IsStructuredFormat(format string) bool {
+	name, _, _ := strings.Cut(format, "=")
+	switch name {
+	case FormatNameJSON, FormatNameYAML,
+		FormatNameJSONPath, FormatNameJSONPathFile,
+		FormatNameGoTemplate, FormatNameGoTemplateFile,
+		FormatNameCustomColumns, FormatNameCustomColumnsFile:
+		return true
+	}
+	return false
+}
+
+// FormatObjects prints data in any structured output format. data is either a
+// single object or a slice of objects; slices are treated as a kubectl List.
+func FormatObjects(data interface{}, format string) error {
+	return WriteObjects(os.Stdout, data, format)
+}
+
+// WriteObjects is FormatObjects writing to w instead of stdout
+func WriteObjects(w io.Writer, data interface{}, format string) error {
+	name, arg, _ := strings.Cut(format, "=")
+
+	switch name {
+	case FormatNameJSON:
+		return writeJSON(w, data)
+	case FormatNameYAML:
+		return writeYAML(w, data)
+	case FormatNameJSONPath:
+		return writeJSONPath(w, data, arg)
+	case FormatNameGoTemplate:
+		return writeGoTemplate(w, data, arg)
+	case FormatNameCustomColumns:
+		return writeCustomColumns(w, data, arg)
+	case FormatNameJSONPathFile, FormatNameGoTemplateFile, FormatNameCustomColumnsFile:
+		contents, err := os.ReadFile(arg)
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %w", err)
+		}
+		switch name {
+		case FormatNameJSONPathFile:
+			return writeJSONPath(w, data, string(contents))
+		case FormatNameGoTemplateFile:
+			return writeGoTemplate(w, data, string(contents))
+		default:
+			spec, err := parseCustomColumnsFile(string(contents))
+			if err != nil {
+				return err
+			}
+			return writeCustomColumns(w, data, spec)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// FormatJSONPath prints data using a kubectl-style JSONPath template
+func FormatJSONPath(data interface{}, tmpl string) error {
+	return writeJSONPath(os.Stdout, data, tmpl)
+}
+
+func writeJSONPath(w io.Writer, data interface{}, tmpl string) error {
+	if tmpl == "" {
+		return fmt.Errorf("jsonpath template must not be empty")
+	}
+
+	parser := jsonpath.New("output").AllowMissingKeys(true)
+	if err := parser.Parse(tmpl); err != nil {
+		return fmt.Errorf("failed to parse jsonpath template: %w", err)
+	}
+
+	generic, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+
+	if err := parser.Execute(w, generic); err != nil {
+		return fmt.Errorf("failed to execute jsonpath template: %w", err)
+	}
+	return nil
+}
+
+// FormatGoTemplate prints data using a Go template
+func FormatGoTemplate(data interface{}, tmpl string) error {
+	return writeGoTemplate(os.Stdout, data, tmpl)
+}
+
+func writeGoTemplate(w io.Writer, data interface{}, tmpl string) error {
+	if tmpl == "" {
+		return fmt.Errorf("go-template must not be empty")
+	}
+
+	t, err := template.New("output").Funcs(template.FuncMap{
+		"base64decode": base64Decode,
+		"exists":       exists,
+	}).Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse go-template: %w", err)
+	}
+
+	generic, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+
+	if err := t.Execute(w, generic); err != nil {
+		return fmt.Errorf("failed to execute go-template: %w", err)
+	}
+	return nil
+}
+
+// customColumn is a single NAME:.json.path column definition
+type customColumn struct {
+	header string
+	parser *jsonpath.JSONPath
+}
+
+// FormatCustomColumns prints data as a table described by a
+// HEADER:.json.path,HEADER:.json.path column specification
+func FormatCustomColumns(data interface{}, spec string) error {
+	return writeCustomColumns(os.Stdout, data, spec)
+}
+
+func writeCustomColumns(w io.Writer, data interface{}, spec string) error {
+	table, err := buildCustomColumnsTable(data, spec)
+	if err != nil {
+		return err
+	}
+	table.RenderTo(w)
+	return nil
+}
+
+func buildCustomColumnsTable(data interface{}, spec string) (*Table, error) {
+	columns, err := parseCustomColumns(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	generic, err := toGeneric(data)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]string, 0, len(columns))
+	for _, col := range columns {
+		headers = append(headers, col.header)
+	}
+
+	table := NewTable(headers)
+	for _, item := range listItems(generic) {
+		row := make([]string, 0, len(columns))
+		for _, col := range columns {
+			row = append(row, evaluateColumn(col.parser, item))
+		}
+		table.AddRow(row)
+	}
+	return table, nil
+}
+
+func parseCustomColumns(spec string) ([]customColumn, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+
+	parts := strings.Split(spec, ",")
+	columns := make([]customColumn, 0, len(parts))
+	for _, part := range parts {
+		header, path, found := strings.Cut(part, ":")
+		if !found || header == "" {
+			return nil, fmt.Errorf("unexpected custom-columns spec: %s, expected <header>:<json-path-expr>", part)
+		}
+
+		parser, err := ParseRelaxedJSONPath(header, path)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, customColumn{header: header, parser: parser})
+	}
+	return columns, nil
+}
+
+// parseCustomColumnsFile converts the two-line file format (headers on the first
+// line, JSONPath expressions on the second) into a column specification
+func parseCustomColumnsFile(contents string) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	lines := []string{}
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read custom-columns file: %w", err)
+	}
+	if len(lines) != 2 {
+		return "", fmt.Errorf("custom-columns file must contain a header line and a JSONPath line")
+	}
+
+	headers := strings.Fields(lines[0])
+	paths := strings.Fields(lines[1])
+	if len(headers) != len(paths) {
+		return "", fmt.Errorf("number of headers (%d) does not match number of JSONPath expressions (%d)", len(headers), len(paths))
+	}
+
+	columns := make([]string, 0, len(headers))
+	for i := range headers {
+		columns = append(columns, headers[i]+":"+paths[i])
+	}
+	return strings.Join(columns, ","), nil
+}
+
+// ParseRelaxedJSONPath compiles a JSONPath expression, accepting the relaxed
+// forms kubectl allows for --sort-by and custom columns (e.g. ".status.phase")
+func ParseRelaxedJSONPath(name, expr string) (*jsonpath.JSONPath, error) {
+	submatches := relaxedJSONPathRegex.FindStringSubmatch(expr)
+	if submatches == nil {
+		return nil, fmt.Errorf("unexpected path string %q, expected a 'name1.name2' or '.name1.name2' or '{name1.name2}' or '{.name1.name2}'", expr)
+	}
+
+	fieldSpec := submatches[1]
+	if fieldSpec == "" {
+		fieldSpec = submatches[2]
+	}
+
+	parser := jsonpath.New(name).AllowMissingKeys(true)
+	if err := parser.Parse(fmt.Sprintf("{.%s}", fieldSpec)); err != nil {
+		return nil, fmt.Errorf("failed to parse jsonpath %q: %w", expr, err)
+	}
+	return parser, nil
+}
+
+// evaluateColumn renders all values matched by parser, joined by commas
+func evaluateColumn(parser *jsonpath.JSONPath, item interface{}) string {
+	results, err := parser.FindResults(item)
+	if err != nil {
+		return "<none>"
+	}
+
+	values := []string{}
+	for _, result := range results {
+		for _, v := range result {
+			if !v.IsValid() || !v.CanInterface() || v.Interface() == nil {
+				continue
+			}
+			values = append(values, formatValue(v.Interface()))
+		}
+	}
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// toGeneric converts typed objects into plain JSON data (maps, slices and
+// scalars) so that templates address fields by their JSON names. Slices are
+// wrapped in a List, the same shape kubectl templates operate on.
+func toGeneric(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(prepareObject(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return convertNumbers(generic), nil
+}
+
+// convertNumbers turns decoded numbers into int64, or float64 when they are
+// not integers, the same types unstructured objects hold. Plain decoding
+// would make every number a float64, printed as 1e+06.
+func convertNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+	return value
+}
+
+// listItems returns the items of a generic List, or the object itself
+func listItems(generic interface{}) []interface{} {
+	if obj, ok := generic.(map[string]interface{}); ok && obj["kind"] == "List" {
+		if items, ok := obj["items"].([]interface{}); ok {
+			return items
+		}
+		return nil
+	}
+	return []interface{}{generic}
+}
+
+func base64Decode(value string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("base64decode: %w", err)
+	}
+	return string(decoded), nil
+}
+
+// exists reports whether the nested map keys are all present in item
+func exists(item interface{}, indices ...string) bool {
+	current := item
+	for _, key := range indices {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if current, ok = obj[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
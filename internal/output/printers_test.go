@@ -0,0 +1,170 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPods() []corev1.Pod {
+	return []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	}
+}
+
+func TestIsStructuredFormat(t *testing.T) {
+	tests := map[string]bool{
+		"json":                          true,
+		"yaml":                          true,
+		"jsonpath={.metadata.name}":     true,
+		"jsonpath-file=tmpl.txt":        true,
+		"go-template={{.kind}}":         true,
+		"go-template-file=tmpl.txt":     true,
+		"custom-columns=NAME:.metadata": true,
+		"custom-columns-file=cols.txt":  true,
+		"table":                         false,
+		"wide":                          false,
+		"xml":                           false,
+	}
+
+	for format, expected := range tests {
+		if result := IsStructuredFormat(format); result != expected {
+			t.Errorf("IsStructuredFormat(%q) = %v, expected %v", format, result, expected)
+		}
+	}
+}
+
+func TestWriteJSONPath(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeJSONPath(&buf, testPods(), `{range .items[*]}{.metadata.name}={.status.phase}{"\n"}{end}`)
+	if err != nil {
+		t.Fatalf("writeJSONPath failed: %v", err)
+	}
+
+	expected := "web-1=Running\nweb-2=Pending\n"
+	if buf.String() != expected {
+		t.Errorf("writeJSONPath() = %q, expected %q", buf.String(), expected)
+	}
+
+	buf.Reset()
+	pod := testPods()[0]
+	if err := writeJSONPath(&buf, &pod, "{.status.podIP}"); err != nil {
+		t.Fatalf("writeJSONPath on single object failed: %v", err)
+	}
+	if buf.String() != "10.0.0.1" {
+		t.Errorf("writeJSONPath() = %q, expected %q", buf.String(), "10.0.0.1")
+	}
+
+	if err := writeJSONPath(&buf, &pod, "{.metadata.name"); err == nil {
+		t.Error("writeJSONPath should fail on an invalid template")
+	}
+}
+
+func TestWriteGoTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeGoTemplate(&buf, testPods(), `{{range .items}}{{.metadata.name}}{{if exists . "status" "podIP"}} {{.status.podIP}}{{end}}
+{{end}}`)
+	if err != nil {
+		t.Fatalf("writeGoTemplate failed: %v", err)
+	}
+
+	expected := "web-1 10.0.0.1\nweb-2\n"
+	if buf.String() != expected {
+		t.Errorf("writeGoTemplate() = %q, expected %q", buf.String(), expected)
+	}
+
+	buf.Reset()
+	secret := map[string]interface{}{"data": map[string]interface{}{"password": "czNjcjN0"}}
+	if err := writeGoTemplate(&buf, secret, "{{.data.password | base64decode}}"); err != nil {
+		t.Fatalf("writeGoTemplate with base64decode failed: %v", err)
+	}
+	if buf.String() != "s3cr3t" {
+		t.Errorf("writeGoTemplate() = %q, expected %q", buf.String(), "s3cr3t")
+	}
+}
+
+func TestTemplatesKeepIntegers(t *testing.T) {
+	grace := int64(1000000)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Generation: 12345678},
+		Spec:       corev1.PodSpec{TerminationGracePeriodSeconds: &grace},
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSONPath(&buf, pod, "{.spec.terminationGracePeriodSeconds} {.metadata.generation}"); err != nil {
+		t.Fatalf("writeJSONPath failed: %v", err)
+	}
+	if buf.String() != "1000000 12345678" {
+		t.Errorf("writeJSONPath() = %q, expected %q", buf.String(), "1000000 12345678")
+	}
+
+	buf.Reset()
+	if err := writeGoTemplate(&buf, pod, "{{.spec.terminationGracePeriodSeconds}} {{.metadata.generation}}"); err != nil {
+		t.Fatalf("writeGoTemplate failed: %v", err)
+	}
+	if buf.String() != "1000000 12345678" {
+		t.Errorf("writeGoTemplate() = %q, expected %q", buf.String(), "1000000 12345678")
+	}
+}
+
+func TestBuildCustomColumnsTable(t *testing.T) {
+	table, err := buildCustomColumnsTable(testPods(), "NAME:.metadata.name,STATUS:{.status.phase},IP:status.podIP")
+	if err != nil {
+		t.Fatalf("buildCustomColumnsTable failed: %v", err)
+	}
+
+	expectedHeaders := []string{"NAME", "STATUS", "IP"}
+	if len(table.headers) != len(expectedHeaders) {
+		t.Fatalf("Expected %d headers, got %d", len(expectedHeaders), len(table.headers))
+	}
+	for i, header := range expectedHeaders {
+		if table.headers[i] != header {
+			t.Errorf("Header %d = %q, expected %q", i, table.headers[i], header)
+		}
+	}
+
+	expectedRows := [][]string{
+		{"web-1", "Running", "10.0.0.1"},
+		{"web-2", "Pending", "<none>"},
+	}
+	if len(table.rows) != len(expectedRows) {
+		t.Fatalf("Expected %d rows, got %d", len(expectedRows), len(table.rows))
+	}
+	for i, row := range expectedRows {
+		for j, cell := range row {
+			if table.rows[i][j] != cell {
+				t.Errorf("Row %d column %d = %q, expected %q", i, j, table.rows[i][j], cell)
+			}
+		}
+	}
+
+	if _, err := buildCustomColumnsTable(testPods(), "NAME"); err == nil {
+		t.Error("buildCustomColumnsTable should fail on a column without a path")
+	}
+	if _, err := buildCustomColumnsTable(testPods(), ""); err == nil {
+		t.Error("buildCustomColumnsTable should fail on an empty spec")
+	}
+}
+
+func TestParseCustomColumnsFile(t *testing.T) {
+	spec, err := parseCustomColumnsFile("NAME          STATUS\n.metadata.name .status.phase\n")
+	if err != nil {
+		t.Fatalf("parseCustomColumnsFile failed: %v", err)
+	}
+	if spec != "NAME:.metadata.name,STATUS:.status.phase" {
+		t.Errorf("parseCustomColumnsFile() = %q", spec)
+	}
+
+	if _, err := parseCustomColumnsFile("NAME STATUS\n.metadata.name\n"); err == nil {
+		t.Error("parseCustomColumnsFile should fail when headers and paths do not match")
+	}
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Screen redraws a block of lines at the top of the terminal, rewriting only
+// the lines that changed since the previous draw.
+type Screen struct {
+	w     io.Writer
+	lines []string
+	drawn bool
+}
+
+// NewScreen creates a screen that draws to w.
+func NewScreen(w io.Writer) *Screen {
+	return &Screen{w: w}
+}
+
+// Draw shows lines, clearing the terminal on the first call.
+func (s *Screen) Draw(lines []string) {
+	var b strings.Builder
+	if !s.drawn {
+		b.WriteString("\033[2J\033[H")
+	}
+
+	for i, line := range lines {
+		if s.drawn && i < len(s.lines) && s.lines[i] == line {
+			continue
+		}
+		// Move to the line, write it and clear what is left of the old one
+		fmt.Fprintf(&b, "\033[%d;1H%s\033[K", i+1, line)
+	}
+	if len(lines) < len(s.lines) {
+		fmt.Fprintf(&b, "\033[%d;1H\033[J", len(lines)+1)
+	}
+	// Park the cursor below the block
+	fmt.Fprintf(&b, "\033[%d;1H", len(lines)+1)
+
+	_, _ = io.WriteString(s.w, b.String())
+	s.lines = append(s.lines[:0], lines...)
+	s.drawn = true
+}
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestScreenDraw(t *testing.T) {
+	var buf bytes.Buffer
+	screen := NewScreen(&buf)
+
+	screen.Draw([]string{"NAME", "api", "db"})
+	expected := "\033[2J\033[H\033[1;1HNAME\033[K\033[2;1Hapi\033[K\033[3;1Hdb\033[K\033[4;1H"
+	if got := buf.String(); got != expected {
+		t.Errorf("First draw:\nexpected %q\ngot      %q", expected, got)
+	}
+
+	buf.Reset()
+	screen.Draw([]string{"NAME", "api", "cache", "db"})
+	expected = "\033[3;1Hcache\033[K\033[4;1Hdb\033[K\033[5;1H"
+	if got := buf.String(); got != expected {
+		t.Errorf("Only changed lines should be redrawn:\nexpected %q\ngot      %q", expected, got)
+	}
+
+	buf.Reset()
+	screen.Draw([]string{"NAME", "api"})
+	expected = "\033[3;1H\033[J\033[3;1H"
+	if got := buf.String(); got != expected {
+		t.Errorf("Removed lines should be cleared:\nexpected %q\ngot      %q", expected, got)
+	}
+}
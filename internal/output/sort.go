@@ -0,0 +1,172 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// SortObjects sorts a slice of objects in place by the value found at a
+// JSONPath expression such as .metadata.creationTimestamp. Objects without
+// the field sort first; reverse flips the order.
+func SortObjects(items interface{}, expr string, reverse bool) error {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("cannot sort %T, expected a slice", items)
+	}
+
+	objects := make([]interface{}, v.Len())
+	for i := range objects {
+		objects[i] = v.Index(i).Interface()
+	}
+
+	return sortBy(expr, reverse, objects, reflect.Swapper(items))
+}
+
+// SortTableRows sorts server-side table rows by a JSONPath expression evaluated
+// against the object embedded in each row
+func SortTableRows(rows []metav1.TableRow, expr string, reverse bool) error {
+	objects := make([]interface{}, len(rows))
+	for i := range rows {
+		objects[i] = rows[i].Object
+	}
+
+	return sortBy(expr, reverse, objects, reflect.Swapper(rows))
+}
+
+func sortBy(expr string, reverse bool, objects []interface{}, swap func(i, j int)) error {
+	parser, err := ParseRelaxedJSONPath("sort", expr)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]interface{}, len(objects))
+	for i, obj := range objects {
+		if keys[i], err = sortKey(parser, obj); err != nil {
+			return err
+		}
+	}
+
+	sort.Stable(&keyedSorter{keys: keys, swap: swap, reverse: reverse})
+	return nil
+}
+
+// keyedSorter sorts precomputed keys and mirrors every swap onto the caller's slice
+type keyedSorter struct {
+	keys    []interface{}
+	swap    func(i, j int)
+	reverse bool
+}
+
+func (s *keyedSorter) Len() int { return len(s.keys) }
+
+func (s *keyedSorter) Less(i, j int) bool {
+	if s.reverse {
+		return compareValues(s.keys[j], s.keys[i]) < 0
+	}
+	return compareValues(s.keys[i], s.keys[j]) < 0
+}
+
+func (s *keyedSorter) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.swap(i, j)
+}
+
+// sortKey returns the first value matched by parser, or nil when it is missing
+func sortKey(parser *jsonpath.JSONPath, obj interface{}) (interface{}, error) {
+	generic, err := toGeneric(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := parser.FindResults(generic)
+	if err != nil {
+		return nil, nil
+	}
+	for _, result := range results {
+		for _, v := range result {
+			if v.IsValid() && v.CanInterface() {
+				return v.Interface(), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// compareValues orders two sort keys. Numbers, timestamps and quantities are
+// compared by value; anything else falls back to string comparison.
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return compareOrdered(x, y)
+		case float64:
+			return compareOrdered(float64(x), y)
+		}
+	case float64:
+		switch y := b.(type) {
+		case float64:
+			return compareOrdered(x, y)
+		case int64:
+			return compareOrdered(x, float64(y))
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return compareOrdered(boolToInt(x), boolToInt(y))
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return compareStrings(x, y)
+		}
+	}
+
+	return compareOrdered(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func compareStrings(a, b string) int {
+	if ta, err := time.Parse(time.RFC3339, a); err == nil {
+		if tb, err := time.Parse(time.RFC3339, b); err == nil {
+			return ta.Compare(tb)
+		}
+	}
+
+	if qa, err := resource.ParseQuantity(a); err == nil {
+		if qb, err := resource.ParseQuantity(b); err == nil {
+			return qa.Cmp(qb)
+		}
+	}
+
+	return compareOrdered(a, b)
+}
+
+func compareOrdered[T float64 | int64 | int | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
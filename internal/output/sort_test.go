@@ -0,0 +1,150 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func podNames(pods []*corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func assertOrder(t *testing.T, got, expected []string) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("Got %v, expected %v", got, expected)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("Got %v, expected %v", got, expected)
+		}
+	}
+}
+
+func TestSortObjects(t *testing.T) {
+	now := time.Now()
+
+	restarts := func(name string, count int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{RestartCount: count}}},
+		}
+	}
+	created := func(name string, age time.Duration) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+	}
+	deadline := func(name string, seconds int64) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: corev1.PodSpec{ActiveDeadlineSeconds: &seconds}}
+	}
+	memory := func(name, request string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(request)},
+				},
+			}}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		pods     []*corev1.Pod
+		expr     string
+		reverse  bool
+		expected []string
+	}{
+		{
+			name:     "numbers",
+			pods:     []*corev1.Pod{restarts("ten", 10), restarts("two", 2), restarts("zero", 0)},
+			expr:     ".status.containerStatuses[0].restartCount",
+			expected: []string{"zero", "two", "ten"},
+		},
+		{
+			name:     "numbers reversed",
+			pods:     []*corev1.Pod{restarts("two", 2), restarts("ten", 10), restarts("zero", 0)},
+			expr:     "{.status.containerStatuses[0].restartCount}",
+			reverse:  true,
+			expected: []string{"ten", "two", "zero"},
+		},
+		{
+			name:     "integers beyond float precision",
+			pods:     []*corev1.Pod{deadline("larger", 1<<53+1), deadline("smaller", 1<<53)},
+			expr:     ".spec.activeDeadlineSeconds",
+			expected: []string{"smaller", "larger"},
+		},
+		{
+			name:     "timestamps",
+			pods:     []*corev1.Pod{created("day", 24*time.Hour), created("minute", time.Minute), created("hour", time.Hour)},
+			expr:     ".metadata.creationTimestamp",
+			expected: []string{"day", "hour", "minute"},
+		},
+		{
+			name:     "quantities",
+			pods:     []*corev1.Pod{memory("gig", "1Gi"), memory("small", "512Mi"), memory("tiny", "900M")},
+			expr:     "spec.containers[0].resources.requests.memory",
+			expected: []string{"small", "tiny", "gig"},
+		},
+		{
+			name:     "strings",
+			pods:     []*corev1.Pod{created("web", 0), created("api", 0), created("db", 0)},
+			expr:     ".metadata.name",
+			expected: []string{"api", "db", "web"},
+		},
+		{
+			name:     "missing values first",
+			pods:     []*corev1.Pod{restarts("one", 1), {ObjectMeta: metav1.ObjectMeta{Name: "none"}}},
+			expr:     ".status.containerStatuses[0].restartCount",
+			expected: []string{"none", "one"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SortObjects(tt.pods, tt.expr, tt.reverse); err != nil {
+				t.Fatalf("SortObjects failed: %v", err)
+			}
+			assertOrder(t, podNames(tt.pods), tt.expected)
+		})
+	}
+}
+
+func TestSortObjectsErrors(t *testing.T) {
+	if err := SortObjects([]*corev1.Pod{}, "{.metadata", false); err == nil {
+		t.Error("SortObjects should fail on an invalid expression")
+	}
+	if err := SortObjects(&corev1.Pod{}, ".metadata.name", false); err == nil {
+		t.Error("SortObjects should fail when not given a slice")
+	}
+}
+
+func TestSortTableRows(t *testing.T) {
+	row := func(name string, replicas int) metav1.TableRow {
+		raw, _ := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name},
+			"spec":     map[string]interface{}{"replicas": replicas},
+		})
+		return metav1.TableRow{Cells: []interface{}{name}, Object: runtime.RawExtension{Raw: raw}}
+	}
+
+	rows := []metav1.TableRow{row("three", 3), row("one", 1), row("two", 2)}
+	if err := SortTableRows(rows, ".spec.replicas", false); err != nil {
+		t.Fatalf("SortTableRows failed: %v", err)
+	}
+
+	names := make([]string, 0, len(rows))
+	for _, r := range rows {
+		names = append(names, r.Cells[0].(string))
+	}
+	assertOrder(t, names, []string{"one", "two", "three"})
+}
@@ -0,0 +1,137 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// ansiPattern matches the color escape sequences added by colorizeRow
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// StreamTable renders a table incrementally, in the same style as Table, for
+// lists that arrive in chunks. Column widths are fixed by the headers and the
+// first flushed batch so later batches line up; a longer cell in a later
+// batch extends past its column rather than being cut.
+type StreamTable struct {
+	w       io.Writer
+	headers []string
+	widths  []int
+	pending [][]string
+	count   int
+	started bool
+
+	statusColIndex int
+	readyColIndex  int
+}
+
+// NewStreamTable creates a streaming table writing to w
+func NewStreamTable(w io.Writer, headers []string) *StreamTable {
+	formatted := make([]string, len(headers))
+	for i, header := range headers {
+		formatted[i] = tablewriter.Title(header)
+	}
+	statusColIndex, readyColIndex := statusColumns(headers)
+
+	return &StreamTable{
+		w:              w,
+		headers:        formatted,
+		statusColIndex: statusColIndex,
+		readyColIndex:  readyColIndex,
+	}
+}
+
+// AddRow queues a row until the next Flush
+func (t *StreamTable) AddRow(row []string) {
+	t.pending = append(t.pending, colorizeRow(row, t.statusColIndex, t.readyColIndex))
+	t.count++
+}
+
+// Len returns the number of rows added so far
+func (t *StreamTable) Len() int {
+	return t.count
+}
+
+// Flush writes the queued rows. The first flush with rows also sizes the
+// columns and writes the header.
+func (t *StreamTable) Flush() {
+	if len(t.pending) == 0 {
+		return
+	}
+	if !t.started {
+		t.start()
+	}
+
+	for _, row := range t.pending {
+		t.writeRow(row)
+	}
+	t.pending = t.pending[:0]
+}
+
+// Close flushes any queued rows and writes the closing border. A table that
+// never received rows is still printed with its header.
+func (t *StreamTable) Close() {
+	t.Flush()
+	if !t.started {
+		t.start()
+	}
+	t.writeSeparator()
+}
+
+// start fixes the column widths from the header and the queued rows, then
+// writes the header
+func (t *StreamTable) start() {
+	t.widths = make([]int, len(t.headers))
+	for i, header := range t.headers {
+		t.widths[i] = visibleWidth(header)
+	}
+	for _, row := range t.pending {
+		for i, cell := range row {
+			if i < len(t.widths) {
+				t.widths[i] = max(t.widths[i], visibleWidth(cell))
+			}
+		}
+	}
+
+	t.writeSeparator()
+	t.writeRow(t.headers)
+	t.writeSeparator()
+	t.started = true
+}
+
+func (t *StreamTable) writeSeparator() {
+	var b strings.Builder
+	b.WriteString("│")
+	for _, width := range t.widths {
+		b.WriteString(strings.Repeat("─", width+2))
+		b.WriteString("│")
+	}
+	_, _ = fmt.Fprintln(t.w, b.String())
+}
+
+func (t *StreamTable) writeRow(row []string) {
+	var b strings.Builder
+	b.WriteString("│")
+	for i, width := range t.widths {
+		cell := ""
+		if i < len(row) {
+			cell = row[i]
+		}
+		b.WriteString(" ")
+		b.WriteString(cell)
+		if pad := width - visibleWidth(cell); pad > 0 {
+			b.WriteString(strings.Repeat(" ", pad))
+		}
+		b.WriteString(" │")
+	}
+	_, _ = fmt.Fprintln(t.w, b.String())
+}
+
+// visibleWidth is the printed width of s, ignoring color codes
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(s, ""))
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestStreamTableMatchesTable(t *testing.T) {
+	headers := []string{"NAME", "STATUS", "AGE"}
+	rows := [][]string{
+		{"web-1", "Running", "5m"},
+		{"api", "Pending", "10d"},
+	}
+
+	var expected, got bytes.Buffer
+	table := NewTable(headers)
+	stream := NewStreamTable(&got, headers)
+	for _, row := range rows {
+		table.AddRow(row)
+		stream.AddRow(row)
+	}
+	table.RenderTo(&expected)
+	stream.Close()
+
+	if got.String() != expected.String() {
+		t.Errorf("StreamTable output differs from Table\ngot:\n%s\nexpected:\n%s", got.String(), expected.String())
+	}
+}
+
+func TestStreamTableStableWidths(t *testing.T) {
+	var buf bytes.Buffer
+	stream := NewStreamTable(&buf, []string{"NAME", "AGE"})
+
+	stream.AddRow([]string{"web-1", "5m"})
+	stream.Flush()
+	stream.AddRow([]string{"api", "10d"})
+	stream.Flush()
+	stream.Close()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("Expected 6 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	width := utf8.RuneCountInString(lines[0])
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) != width {
+			t.Errorf("Line %q does not match the width of the first batch", line)
+		}
+	}
+	if stream.Len() != 2 {
+		t.Errorf("Len() = %d, expected 2", stream.Len())
+	}
+}
+
+func TestStreamTableEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	stream := NewStreamTable(&buf, []string{"NAME"})
+
+	stream.Flush()
+	if buf.Len() != 0 {
+		t.Errorf("Flush without rows should not print anything, got:\n%s", buf.String())
+	}
+
+	stream.Close()
+	if !strings.Contains(buf.String(), "NAME") {
+		t.Errorf("Close should print the header of an empty table, got:\n%s", buf.String())
+	}
+}
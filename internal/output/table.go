@@ -1,6 +1,7 @@
 package output
 
 import (
+	"io"
 	"os"
 	"strings"
 
@@ -26,9 +27,19 @@ func (t *Table) AddRow(row []string) {
 	t.rows = append(t.rows, row)
 }
 
+// Len returns the number of rows in the table
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
 // Render renders the table to stdout
 func (t *Table) Render() {
-	table := tablewriter.NewWriter(os.Stdout)
+	t.RenderTo(os.Stdout)
+}
+
+// RenderTo renders the table to w
+func (t *Table) RenderTo(w io.Writer) {
+	table := tablewriter.NewWriter(w)
 
 	// Enable borders and separators for visible table lines
 	table.SetBorder(true)
@@ -54,10 +65,21 @@ func (t *Table) Render() {
 		table.SetHeaderLine(true)
 	}
 
-	// Find STATUS and READY column indices
+	statusColIndex, readyColIndex := statusColumns(t.headers)
+
+	// Add rows with colorized status
+	for _, row := range t.rows {
+		table.Append(colorizeRow(row, statusColIndex, readyColIndex))
+	}
+
+	table.Render()
+}
+
+// statusColumns finds the STATUS and READY column indices, -1 when absent
+func statusColumns(headers []string) (int, int) {
 	statusColIndex := -1
 	readyColIndex := -1
-	for i, header := range t.headers {
+	for i, header := range headers {
 		headerUpper := strings.ToUpper(header)
 		if headerUpper == "STATUS" {
 			statusColIndex = i
@@ -66,47 +88,46 @@ func (t *Table) Render() {
 			readyColIndex = i
 		}
 	}
+	return statusColIndex, readyColIndex
+}
 
-	// Add rows with colorized status
-	for _, row := range t.rows {
-		coloredRow := make([]string, len(row))
-		for i, cell := range row {
-			// Colorize STATUS column if it exists
-			switch {
-			case i == statusColIndex && statusColIndex >= 0:
-				status := strings.TrimSpace(cell)
-				if isStatusField(status) {
-					coloredRow[i] = ColorizeStatus(status)
-				} else {
-					coloredRow[i] = cell
-				}
-			case i == readyColIndex && readyColIndex >= 0:
-				// Colorize READY column (e.g., "1/1", "2/3")
-				ready := strings.TrimSpace(cell)
-				if strings.Contains(ready, "/") {
-					parts := strings.Split(ready, "/")
-					if len(parts) == 2 {
-						if parts[0] == parts[1] {
-							// All ready - green
-							coloredRow[i] = StatusReady.Sprint(ready)
-						} else {
-							// Partially ready - yellow
-							coloredRow[i] = StatusPending.Sprint(ready)
-						}
+// colorizeRow colors the STATUS and READY cells of a row
+func colorizeRow(row []string, statusColIndex, readyColIndex int) []string {
+	coloredRow := make([]string, len(row))
+	for i, cell := range row {
+		// Colorize STATUS column if it exists
+		switch {
+		case i == statusColIndex && statusColIndex >= 0:
+			status := strings.TrimSpace(cell)
+			if isStatusField(status) {
+				coloredRow[i] = ColorizeStatus(status)
+			} else {
+				coloredRow[i] = cell
+			}
+		case i == readyColIndex && readyColIndex >= 0:
+			// Colorize READY column (e.g., "1/1", "2/3")
+			ready := strings.TrimSpace(cell)
+			if strings.Contains(ready, "/") {
+				parts := strings.Split(ready, "/")
+				if len(parts) == 2 {
+					if parts[0] == parts[1] {
+						// All ready - green
+						coloredRow[i] = StatusReady.Sprint(ready)
 					} else {
-						coloredRow[i] = ready
+						// Partially ready - yellow
+						coloredRow[i] = StatusPending.Sprint(ready)
 					}
 				} else {
 					coloredRow[i] = ready
 				}
-			default:
-				coloredRow[i] = cell
+			} else {
+				coloredRow[i] = ready
 			}
+		default:
+			coloredRow[i] = cell
 		}
-		table.Append(coloredRow)
 	}
-
-	table.Render()
+	return coloredRow
 }
 
 // isStatusField checks if a string looks like a status field
@@ -115,12 +136,12 @@ func isStatusField(s string) bool {
 		"Running", "Pending", "Failed", "Succeeded", "Error",
 		"Ready", "NotReady", "Active", "Inactive",
 		"ContainerCreating", "PodInitializing", "CrashLoopBackOff",
-		"ImagePullBackOff", "ErrImagePull", "Completed",
+		"ImagePullBackOff", "ErrImagePull", "Completed", "Terminating",
 	}
 	for _, status := range statuses {
 		if s == status {
 			return true
 		}
 	}
-	return false
+	return IsFailedStatus(s) || strings.HasPrefix(s, "Init:")
 }